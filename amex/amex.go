@@ -0,0 +1,274 @@
+// package amex provides the parsing functions to process the american express credit card statements
+package amex
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/muly/bank-tx/model"
+	"github.com/muly/bank-tx/util"
+)
+
+// payOverTimeMarker is printed after the amount of the charges that are eligible for Pay Over Time
+const payOverTimeMarker = "⧫"
+
+type Transaction struct {
+	TransactionDate time.Time
+	Description     string
+	CardMember      string
+	CardEnding      string
+	Amount          float64
+	PayOverTime     bool
+	Category        string
+}
+
+// Plan holds a Plan It plan balance
+type Plan struct {
+	ID                string
+	Description       string
+	Balance           float64
+	MonthlyPayment    float64
+	PaymentsRemaining int
+}
+
+type Statement struct {
+	AccountNumber      string
+	PeriodStartDate    time.Time
+	PeriodEndDate      time.Time
+	BeginningBalance   float64
+	EndingBalance      float64
+	PayOverTimeBalance float64
+	Plans              []Plan
+	Transactions       []Transaction
+}
+
+var (
+	reAccount     = regexp.MustCompile(`^Account Ending (\d-\d{5})$`)
+	reCardEnding  = regexp.MustCompile(`^Card Ending (\d-\d{5})$`)
+	rePeriodDate  = regexp.MustCompile(`^(Opening|Closing) Date (\d{2}/\d{2}/\d{2})$`)
+	reSummary     = regexp.MustCompile(`^(Previous Balance|Payments/Credits|New Charges|Fees|Interest Charged|New Balance) ([-+]?\$[\d,]+\.\d{2})$`)
+	rePayOverTime = regexp.MustCompile(`^Pay Over Time Balance (\$[\d,]+\.\d{2})$`)
+	rePlan        = regexp.MustCompile(`^(PLAN\d+)\s+(.+?)\s+Plan Balance (\$[\d,]+\.\d{2})\s+Monthly Payment (\$[\d,]+\.\d{2})\s+Payments Remaining (\d+)$`)
+	reTransaction = regexp.MustCompile(`^(\d{2}/\d{2}/\d{2})\*?\s+(.+?)\s+(-?\$[\d,]+\.\d{2})\s*(` + payOverTimeMarker + `?)$`)
+)
+
+// ParseStatement parses the statement
+func ParseStatement(data string) (*Statement, error) {
+	lines := strings.Split(data, "\n")
+
+	var statement Statement
+	summary := map[string]float64{}
+	inCategory := ""
+	cardMember, cardEnding := "", ""
+	// current is the index of the transaction that continuation lines of the description are appended to
+	current := -1
+
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+
+		if line == "" {
+			continue
+		}
+
+		// Parse Account Number
+		if match := reAccount.FindStringSubmatch(line); match != nil {
+			statement.AccountNumber = match[1]
+			continue
+		}
+
+		// Parse Statement Period
+		if match := rePeriodDate.FindStringSubmatch(line); match != nil {
+			date, err := time.Parse("01/02/06", match[2])
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse %s date: %v", strings.ToLower(match[1]), err)
+			}
+			if match[1] == "Opening" {
+				statement.PeriodStartDate = date
+			} else {
+				statement.PeriodEndDate = date
+			}
+			continue
+		}
+
+		// Parse the account summary
+		if match := reSummary.FindStringSubmatch(line); match != nil {
+			amount, err := util.ParseFloat(match[2])
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse %s: %s, error: %v", match[1], match[2], err)
+			}
+			summary[match[1]] = amount
+			continue
+		}
+
+		// Parse Pay Over Time and Plan It balances
+		if match := rePayOverTime.FindStringSubmatch(line); match != nil {
+			balance, err := util.ParseFloat(match[1])
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse pay over time balance: %s, error: %v", match[1], err)
+			}
+			statement.PayOverTimeBalance = balance
+			continue
+		}
+		if match := rePlan.FindStringSubmatch(line); match != nil {
+			plan, err := parsePlan(match)
+			if err != nil {
+				return nil, err
+			}
+			statement.Plans = append(statement.Plans, *plan)
+			continue
+		}
+
+		if line == "Payments and Credits" ||
+			line == "New Charges" ||
+			line == "Fees" ||
+			line == "Interest Charged" {
+			inCategory = line
+			cardMember, cardEnding = "", ""
+			current = -1
+			continue // Skip the header line
+		}
+
+		if line == "Detail" || line == "Account Summary" || line == "Pay Over Time and Plan It" {
+			continue // Skip the header line
+		}
+
+		if strings.HasPrefix(line, "Total ") {
+			current = -1
+			continue // Skip the subtotal line
+		}
+
+		// The card member name is the line right before its card ending
+		if i+1 < len(lines) && reCardEnding.MatchString(strings.TrimSpace(lines[i+1])) && inCategory != "" {
+			cardMember = line
+			cardEnding = reCardEnding.FindStringSubmatch(strings.TrimSpace(lines[i+1]))[1]
+			current = -1
+			i++
+			continue
+		}
+
+		if match := reTransaction.FindStringSubmatch(line); match != nil && inCategory != "" {
+			transactionDate, err := time.Parse("01/02/06", match[1])
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse transaction date: %v", err)
+			}
+			amount, err := util.ParseFloat(match[3])
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse amount: %s, error: %v", match[3], err)
+			}
+
+			statement.Transactions = append(statement.Transactions, Transaction{
+				TransactionDate: transactionDate,
+				Description:     match[2],
+				CardMember:      cardMember,
+				CardEnding:      cardEnding,
+				Amount:          amount,
+				PayOverTime:     match[4] == payOverTimeMarker,
+				Category:        inCategory,
+			})
+			current = len(statement.Transactions) - 1
+			continue
+		}
+
+		// Descriptions span multiple lines, the extra lines carry the merchant location and type
+		if current >= 0 {
+			statement.Transactions[current].Description += " " + line
+			continue
+		}
+
+		log.Printf("unprocessed line: %v", line)
+	}
+
+	// Validate balances
+	calculatedEndBalance := summary["Previous Balance"] + summary["Payments/Credits"] + summary["New Charges"] + summary["Fees"] + summary["Interest Charged"]
+	if util.RoundToTwoDecimal(calculatedEndBalance) != util.RoundToTwoDecimal(summary["New Balance"]) {
+		return nil, fmt.Errorf("summary balance validation failed: previous balance %v, payments/credits %v, new charges %v, fees %v, interest charged %v, new balance %v",
+			summary["Previous Balance"], summary["Payments/Credits"], summary["New Charges"], summary["Fees"], summary["Interest Charged"], summary["New Balance"])
+	}
+
+	totals := map[string]float64{}
+	for _, tx := range statement.Transactions {
+		totals[tx.Category] += tx.Amount
+	}
+	for category, summaryName := range map[string]string{
+		"Payments and Credits": "Payments/Credits",
+		"New Charges":          "New Charges",
+		"Fees":                 "Fees",
+		"Interest Charged":     "Interest Charged",
+	} {
+		if util.RoundToTwoDecimal(totals[category]) != util.RoundToTwoDecimal(summary[summaryName]) {
+			return nil, fmt.Errorf("%s total mismatch: expected %.2f, got %.2f", category, summary[summaryName], totals[category])
+		}
+	}
+
+	statement.BeginningBalance = summary["Previous Balance"]
+	statement.EndingBalance = summary["New Balance"]
+
+	return &statement, nil
+}
+
+// CardMembers returns the transactions grouped by the card member
+func (s Statement) CardMembers() map[string][]Transaction {
+	members := map[string][]Transaction{}
+	for _, tx := range s.Transactions {
+		if tx.CardMember == "" {
+			continue // account level entries like fees and interest
+		}
+		members[tx.CardMember] = append(members[tx.CardMember], tx)
+	}
+	return members
+}
+
+// ToModel converts the statement to the bank neutral model, the card ending identifies the card member of each transaction
+func (s Statement) ToModel() model.Statement {
+	transactions := make([]model.Transaction, 0, len(s.Transactions))
+	for _, tx := range s.Transactions {
+		transactions = append(transactions, model.Transaction{
+			TransactionDate: tx.TransactionDate,
+			PostingDate:     tx.TransactionDate,
+			Description:     tx.Description,
+			AccountNumber:   tx.CardEnding,
+			Amount:          tx.Amount,
+			Category:        tx.Category,
+		})
+	}
+
+	return model.Statement{
+		Institution:      model.Amex,
+		AccountType:      model.CreditCard,
+		AccountNumber:    s.AccountNumber,
+		PeriodStartDate:  s.PeriodStartDate,
+		PeriodEndDate:    s.PeriodEndDate,
+		BeginningBalance: s.BeginningBalance,
+		EndingBalance:    s.EndingBalance,
+		Transactions:     transactions,
+	}
+}
+
+// Helper functions
+
+func parsePlan(match []string) (*Plan, error) {
+	balance, err := util.ParseFloat(match[3])
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse plan balance: %s, error: %v", match[3], err)
+	}
+	monthlyPayment, err := util.ParseFloat(match[4])
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse plan monthly payment: %s, error: %v", match[4], err)
+	}
+	remaining, err := strconv.Atoi(match[5])
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse plan payments remaining: %s, error: %v", match[5], err)
+	}
+
+	return &Plan{
+		ID:                match[1],
+		Description:       match[2],
+		Balance:           balance,
+		MonthlyPayment:    monthlyPayment,
+		PaymentsRemaining: remaining,
+	}, nil
+}
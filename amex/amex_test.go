@@ -0,0 +1,181 @@
+package amex
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func loadSample(t *testing.T) string {
+	data, err := os.ReadFile("sample.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestParseStatement_transactions(t *testing.T) {
+	s, err := ParseStatement(loadSample(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name            string
+		index           int
+		wantDescription string
+		wantCardMember  string
+		wantCardEnding  string
+		wantAmount      float64
+		wantPayOverTime bool
+		wantCategory    string
+	}{
+		{
+			name:            "case 1: payment with the posted marker after the date",
+			index:           0,
+			wantDescription: "MOBILE PAYMENT - THANK YOU",
+			wantCardMember:  "SOME NAME",
+			wantCardEnding:  "1-23456",
+			wantAmount:      -1234.56,
+			wantCategory:    "Payments and Credits",
+		},
+		{
+			name:            "case 2: pay over time charge with a three line description",
+			index:           1,
+			wantDescription: "COSTCO WHSE #1111 TOWN ST WAREHOUSE CLUB",
+			wantCardMember:  "SOME NAME",
+			wantCardEnding:  "1-23456",
+			wantAmount:      145.67,
+			wantPayOverTime: true,
+			wantCategory:    "New Charges",
+		},
+		{
+			name:            "case 3: continuation line right before the next card member",
+			index:           2,
+			wantDescription: "AMAZON MARKETPLACE AMZN.COM/BILL WA",
+			wantCardMember:  "SOME NAME",
+			wantCardEnding:  "1-23456",
+			wantAmount:      62.95,
+			wantPayOverTime: true,
+			wantCategory:    "New Charges",
+		},
+		{
+			name:            "case 4: charge of the additional card member without the marker",
+			index:           3,
+			wantDescription: "TST*WATERPARK - KIOSK 1 TOWN ST",
+			wantCardMember:  "OTHER NAME",
+			wantCardEnding:  "1-65432",
+			wantAmount:      20,
+			wantCategory:    "New Charges",
+		},
+		{
+			name:            "case 5: account level fee without a card member",
+			index:           4,
+			wantDescription: "ANNUAL MEMBERSHIP FEE",
+			wantAmount:      95,
+			wantCategory:    "Fees",
+		},
+		{
+			name:            "case 6: interest",
+			index:           5,
+			wantDescription: "Interest Charge on Pay Over Time Purchases",
+			wantAmount:      0,
+			wantCategory:    "Interest Charged",
+		},
+	}
+
+	if len(s.Transactions) != len(tests) {
+		t.Fatalf("ParseStatement() got %d transactions, want %d", len(s.Transactions), len(tests))
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx := s.Transactions[tt.index]
+			if tx.Description != tt.wantDescription {
+				t.Errorf("Description = %q, want %q", tx.Description, tt.wantDescription)
+			}
+			if tx.CardMember != tt.wantCardMember || tx.CardEnding != tt.wantCardEnding {
+				t.Errorf("card member = %q %q, want %q %q", tx.CardMember, tx.CardEnding, tt.wantCardMember, tt.wantCardEnding)
+			}
+			if tx.Amount != tt.wantAmount {
+				t.Errorf("Amount = %v, want %v", tx.Amount, tt.wantAmount)
+			}
+			if tx.PayOverTime != tt.wantPayOverTime {
+				t.Errorf("PayOverTime = %v, want %v", tx.PayOverTime, tt.wantPayOverTime)
+			}
+			if tx.Category != tt.wantCategory {
+				t.Errorf("Category = %q, want %q", tx.Category, tt.wantCategory)
+			}
+		})
+	}
+
+	members := s.CardMembers()
+	if len(members["SOME NAME"]) != 3 || len(members["OTHER NAME"]) != 1 {
+		t.Errorf("CardMembers() got %d and %d transactions, want 3 and 1", len(members["SOME NAME"]), len(members["OTHER NAME"]))
+	}
+}
+
+func TestParseStatement_plans(t *testing.T) {
+	tests := []struct {
+		name      string
+		replace   string
+		with      string
+		wantPlans []Plan
+		wantErr   string
+	}{
+		{
+			name:      "case 1: plan it plan",
+			wantPlans: []Plan{{ID: "PLAN0001", Description: "APPLE STORE", Balance: 700, MonthlyPayment: 100, PaymentsRemaining: 7}},
+		},
+		{
+			name:    "case 2: two plans",
+			replace: "Payments Remaining 7\n",
+			with:    "Payments Remaining 7\nPLAN0002 BEST BUY TV Plan Balance $1,200.00 Monthly Payment $150.00 Payments Remaining 8\n",
+			wantPlans: []Plan{
+				{ID: "PLAN0001", Description: "APPLE STORE", Balance: 700, MonthlyPayment: 100, PaymentsRemaining: 7},
+				{ID: "PLAN0002", Description: "BEST BUY TV", Balance: 1200, MonthlyPayment: 150, PaymentsRemaining: 8},
+			},
+		},
+		{
+			name:    "case 3: section total mismatch",
+			replace: "12/22/23 TST*WATERPARK - KIOSK 1 $20.00",
+			with:    "12/22/23 TST*WATERPARK - KIOSK 1 $21.00",
+			wantErr: "New Charges total mismatch",
+		},
+		{
+			name:    "case 4: summary does not add up",
+			replace: "New Balance $323.62",
+			with:    "New Balance $332.62",
+			wantErr: "summary balance validation failed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := loadSample(t)
+			if tt.replace != "" {
+				data = strings.Replace(data, tt.replace, tt.with, 1)
+			}
+			s, err := ParseStatement(data)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("ParseStatement() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseStatement() unexpected error = %v", err)
+			}
+			if s.PayOverTimeBalance != 208.62 {
+				t.Errorf("PayOverTimeBalance = %v, want 208.62", s.PayOverTimeBalance)
+			}
+			if len(s.Plans) != len(tt.wantPlans) {
+				t.Fatalf("Plans = %+v, want %+v", s.Plans, tt.wantPlans)
+			}
+			for i := range tt.wantPlans {
+				if s.Plans[i] != tt.wantPlans[i] {
+					t.Errorf("Plans[%d] = %+v, want %+v", i, s.Plans[i], tt.wantPlans[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package amex
+
+import (
+	"fmt"
+)
+
+func ExampleParseStatement() {
+	statement := `
+American Express
+Blue Cash Preferred Card
+Prepared for
+SOME NAME
+Account Ending 1-23456
+Opening Date 12/14/23
+Closing Date 01/12/24
+
+Account Summary
+Previous Balance $1,234.56
+Payments/Credits -$1,234.56
+New Charges +$228.62
+Fees +$95.00
+Interest Charged +$0.00
+New Balance $323.62
+
+Pay Over Time and Plan It
+Pay Over Time Balance $208.62
+PLAN0001 APPLE STORE Plan Balance $700.00 Monthly Payment $100.00 Payments Remaining 7
+
+Payments and Credits
+Detail
+SOME NAME
+Card Ending 1-23456
+01/02/24* MOBILE PAYMENT - THANK YOU -$1,234.56
+Total Payments and Credits -$1,234.56
+
+New Charges
+Detail
+SOME NAME
+Card Ending 1-23456
+12/15/23 COSTCO WHSE #1111 $145.67⧫
+TOWN ST
+WAREHOUSE CLUB
+12/20/23 AMAZON MARKETPLACE AMZN.COM/BILL $62.95⧫
+WA
+OTHER NAME
+Card Ending 1-65432
+12/22/23 TST*WATERPARK - KIOSK 1 $20.00
+TOWN ST
+Total New Charges $228.62
+
+Fees
+01/12/24 ANNUAL MEMBERSHIP FEE $95.00
+Total Fees for this Period $95.00
+
+Interest Charged
+01/12/24 Interest Charge on Pay Over Time Purchases $0.00
+Total Interest Charged for this Period $0.00
+	`
+
+	s, err := ParseStatement(statement)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	for _, tx := range s.Transactions {
+		fmt.Printf("%+v\n", tx)
+	}
+	fmt.Printf("Pay Over Time Balance: %.2f\n", s.PayOverTimeBalance)
+	for _, p := range s.Plans {
+		fmt.Printf("%+v\n", p)
+	}
+
+	// Output:
+	// {TransactionDate:2024-01-02 00:00:00 +0000 UTC Description:MOBILE PAYMENT - THANK YOU CardMember:SOME NAME CardEnding:1-23456 Amount:-1234.56 PayOverTime:false Category:Payments and Credits}
+	// {TransactionDate:2023-12-15 00:00:00 +0000 UTC Description:COSTCO WHSE #1111 TOWN ST WAREHOUSE CLUB CardMember:SOME NAME CardEnding:1-23456 Amount:145.67 PayOverTime:true Category:New Charges}
+	// {TransactionDate:2023-12-20 00:00:00 +0000 UTC Description:AMAZON MARKETPLACE AMZN.COM/BILL WA CardMember:SOME NAME CardEnding:1-23456 Amount:62.95 PayOverTime:true Category:New Charges}
+	// {TransactionDate:2023-12-22 00:00:00 +0000 UTC Description:TST*WATERPARK - KIOSK 1 TOWN ST CardMember:OTHER NAME CardEnding:1-65432 Amount:20 PayOverTime:false Category:New Charges}
+	// {TransactionDate:2024-01-12 00:00:00 +0000 UTC Description:ANNUAL MEMBERSHIP FEE CardMember: CardEnding: Amount:95 PayOverTime:false Category:Fees}
+	// {TransactionDate:2024-01-12 00:00:00 +0000 UTC Description:Interest Charge on Pay Over Time Purchases CardMember: CardEnding: Amount:0 PayOverTime:false Category:Interest Charged}
+	// Pay Over Time Balance: 208.62
+	// {ID:PLAN0001 Description:APPLE STORE Balance:700 MonthlyPayment:100 PaymentsRemaining:7}
+}
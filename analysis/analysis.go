@@ -0,0 +1,16 @@
+// package analysis provides the analyses of the transaction history across the statements
+package analysis
+
+import (
+	"time"
+
+	"github.com/muly/bank-tx/model"
+)
+
+// date returns the transaction date, the posting date when the statement has none
+func date(tx model.Transaction) time.Time {
+	if tx.TransactionDate.IsZero() {
+		return tx.PostingDate
+	}
+	return tx.TransactionDate
+}
@@ -0,0 +1,253 @@
+package analysis
+
+import (
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/muly/bank-tx/model"
+	"github.com/muly/bank-tx/util"
+)
+
+// Balance is the balance of an account at the end of a day
+type Balance struct {
+	Date    time.Time
+	Balance float64
+}
+
+// Account is the balance history of an account
+type Account struct {
+	Institution   string
+	AccountType   string
+	AccountNumber string
+	// Balances are the daily balances in date order, on the statement start and end dates and the days with transactions
+	Balances []Balance
+}
+
+// Liability tells whether the balance of the account is owed, the credit card balances are liabilities and the others assets
+func (a Account) Liability() bool {
+	return a.AccountType == model.CreditCard
+}
+
+// Balances returns the balance history of every account of the statements. The balance starts with the beginning
+// balance of each statement, moves with the transactions by their posting date and ends with the ending balance.
+func Balances(statements []model.Statement) []Account {
+	sorted := append([]model.Statement(nil), statements...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].PeriodStartDate.Before(sorted[j].PeriodStartDate)
+	})
+
+	accounts := map[string]*Account{}
+	days := map[string]map[time.Time]float64{}
+	var keys []string
+	for _, s := range sorted {
+		key := s.Institution + "|" + s.AccountNumber
+		if accounts[key] == nil {
+			accounts[key] = &Account{Institution: s.Institution, AccountType: s.AccountType, AccountNumber: s.AccountNumber}
+			days[key] = map[time.Time]float64{}
+			keys = append(keys, key)
+		}
+
+		transactions := append([]model.Transaction(nil), s.Transactions...)
+		sort.SliceStable(transactions, func(i, j int) bool {
+			return transactions[i].PostingDate.Before(transactions[j].PostingDate)
+		})
+
+		balance := s.BeginningBalance
+		days[key][s.PeriodStartDate] = balance
+		for _, tx := range transactions {
+			balance += tx.Amount
+			days[key][tx.PostingDate] = util.RoundToTwoDecimal(balance)
+		}
+		days[key][s.PeriodEndDate] = s.EndingBalance
+	}
+	sort.Strings(keys)
+
+	result := make([]Account, 0, len(keys))
+	for _, key := range keys {
+		a := accounts[key]
+		for date, balance := range days[key] {
+			a.Balances = append(a.Balances, Balance{Date: date, Balance: balance})
+		}
+		sort.Slice(a.Balances, func(i, j int) bool {
+			return a.Balances[i].Date.Before(a.Balances[j].Date)
+		})
+		result = append(result, *a)
+	}
+	return result
+}
+
+// Position is the combined position of the accounts at the end of a day, Liabilities is the amount owed on the cards
+type Position struct {
+	Date        time.Time
+	Assets      float64
+	Liabilities float64
+	Net         float64
+}
+
+// Timeline returns the net position on every day a balance changes. The accounts count from their first statement on,
+// with their last balance carried forward between the balance dates.
+func Timeline(accounts []Account) []Position {
+	changes := map[time.Time][]struct {
+		account int
+		balance float64
+	}{}
+	for i, a := range accounts {
+		for _, b := range a.Balances {
+			changes[b.Date] = append(changes[b.Date], struct {
+				account int
+				balance float64
+			}{i, b.Balance})
+		}
+	}
+
+	dates := make([]time.Time, 0, len(changes))
+	for date := range changes {
+		dates = append(dates, date)
+	}
+	sort.Slice(dates, func(i, j int) bool { return dates[i].Before(dates[j]) })
+
+	current := make([]float64, len(accounts))
+	timeline := make([]Position, 0, len(dates))
+	for _, date := range dates {
+		for _, c := range changes[date] {
+			current[c.account] = c.balance
+		}
+		p := Position{Date: date}
+		for i, a := range accounts {
+			if a.Liability() {
+				p.Liabilities += current[i]
+			} else {
+				p.Assets += current[i]
+			}
+		}
+		p.Assets, p.Liabilities = util.RoundToTwoDecimal(p.Assets), util.RoundToTwoDecimal(p.Liabilities)
+		p.Net = util.RoundToTwoDecimal(p.Assets - p.Liabilities)
+		timeline = append(timeline, p)
+	}
+	return timeline
+}
+
+// AccountMonth is the range of the balance of an account in a month
+type AccountMonth struct {
+	Institution   string
+	AccountNumber string
+	Min           float64
+	MinDate       time.Time
+	Max           float64
+	MaxDate       time.Time
+	// Ending is the balance at the end of the month
+	Ending float64
+}
+
+// cardPayment matches the descriptions of the payments to the credit cards, e.g. "PAYMENT - THANK YOU"
+var cardPayment = regexp.MustCompile(`(?i)PAYMENT|THANK YOU|AUTOPAY`)
+
+// CashFlow is the money coming into and leaving the accounts in a month, "2006-01"
+type CashFlow struct {
+	Month   string
+	Income  float64
+	Outflow float64
+	Net     float64
+	// SavingsRate is the share of the income not spent, 0 without income
+	SavingsRate float64
+	// Position is the net position at the end of the month
+	Position Position
+	Accounts []AccountMonth
+}
+
+// MonthlyCashFlow returns the cash flow of every month from the first to the last statement. The transfers between
+// the accounts, the transactions with a TransferID, are neither income nor outflow, nor are the payments to the credit
+// cards whose checking side is not in the statements. The other money coming into a credit card is a refund, it reduces
+// the outflow.
+func MonthlyCashFlow(statements []model.Statement) []CashFlow {
+	accounts := Balances(statements)
+	timeline := Timeline(accounts)
+	if len(timeline) == 0 {
+		return nil
+	}
+
+	first, last := timeline[0].Date, timeline[len(timeline)-1].Date
+	var months []CashFlow
+	index := map[string]int{}
+	for month := time.Date(first.Year(), first.Month(), 1, 0, 0, 0, 0, first.Location()); !month.After(last); month = month.AddDate(0, 1, 0) {
+		index[month.Format("2006-01")] = len(months)
+		months = append(months, CashFlow{Month: month.Format("2006-01")})
+	}
+
+	for _, s := range statements {
+		for _, tx := range s.Transactions {
+			i, ok := index[tx.PostingDate.Format("2006-01")]
+			if !ok || tx.TransferID != "" {
+				continue
+			}
+			amount := s.Flow(tx)
+			if s.AccountType == model.CreditCard && amount > 0 && cardPayment.MatchString(tx.Description) {
+				continue
+			}
+			if amount < 0 || s.AccountType == model.CreditCard {
+				months[i].Outflow -= amount
+			} else {
+				months[i].Income += amount
+			}
+		}
+	}
+
+	p := 0
+	for i := range months {
+		m := &months[i]
+		m.Income, m.Outflow = util.RoundToTwoDecimal(m.Income), util.RoundToTwoDecimal(m.Outflow)
+		m.Net = util.RoundToTwoDecimal(m.Income - m.Outflow)
+		if m.Income > 0 {
+			m.SavingsRate = m.Net / m.Income
+		}
+
+		start, _ := time.Parse("2006-01", m.Month)
+		end := start.AddDate(0, 1, 0)
+		for p < len(timeline) && timeline[p].Date.Before(end) {
+			p++
+		}
+		if p > 0 {
+			m.Position = timeline[p-1]
+		}
+
+		for _, a := range accounts {
+			if am, ok := accountMonth(a, start, end); ok {
+				m.Accounts = append(m.Accounts, am)
+			}
+		}
+	}
+	return months
+}
+
+// accountMonth returns the balance range of the account from the start to before the end, including the balance
+// carried into the month, false when the account has no balance yet
+func accountMonth(a Account, start, end time.Time) (AccountMonth, bool) {
+	am := AccountMonth{Institution: a.Institution, AccountNumber: a.AccountNumber}
+	found := false
+	add := func(date time.Time, balance float64) {
+		if !found || balance < am.Min {
+			am.Min, am.MinDate = balance, date
+		}
+		if !found || balance > am.Max {
+			am.Max, am.MaxDate = balance, date
+		}
+		am.Ending = balance
+		found = true
+	}
+
+	for i, b := range a.Balances {
+		if !b.Date.Before(end) {
+			break
+		}
+		if !b.Date.Before(start) {
+			add(b.Date, b.Balance)
+			continue
+		}
+		// the balance carried into the month from the last day before it, unless the history of the account ended
+		if i+1 < len(a.Balances) && a.Balances[i+1].Date.After(start) {
+			add(start, b.Balance)
+		}
+	}
+	return am, found
+}
@@ -0,0 +1,79 @@
+package analysis
+
+import (
+	"testing"
+	"time"
+
+	"github.com/muly/bank-tx/model"
+)
+
+func TestMonthlyCashFlow(t *testing.T) {
+	day := func(month time.Month, d int) time.Time { return time.Date(2024, month, d, 0, 0, 0, 0, time.UTC) }
+	statements := []model.Statement{
+		{Institution: model.TD, AccountType: model.Checking, AccountNumber: "123-4567890",
+			PeriodStartDate: day(time.March, 21), PeriodEndDate: day(time.April, 20), BeginningBalance: 1000, EndingBalance: 3800,
+			Transactions: []model.Transaction{
+				{PostingDate: day(time.March, 25), Description: "ACH DEPOSIT, ACME CORP PAYROLL", Amount: 4000},
+				{PostingDate: day(time.April, 2), Description: "ELECTRONIC PMT-WEB, EEERERERERE MTG PAYMENTS ****311244", Amount: -1000},
+				{PostingDate: day(time.April, 10), Description: "TD BILL PAY SERV, BANK OF AMERICA ONLINE PMT TDB****34454454", Amount: -200, TransferID: "card"},
+			}},
+		{Institution: model.BofA, AccountType: model.CreditCard, AccountNumber: "1234",
+			PeriodStartDate: day(time.March, 28), PeriodEndDate: day(time.April, 27), BeginningBalance: 300, EndingBalance: 220,
+			Transactions: []model.Transaction{
+				{PostingDate: day(time.April, 5), Description: "COSTCO WHSE #1111 TOWN STATE", Amount: 150},
+				{PostingDate: day(time.April, 8), Description: "COSTCO WHSE #1111 TOWN STATE", Amount: -30},
+				{PostingDate: day(time.April, 12), Description: "PAYMENT - THANK YOU", Amount: -200, TransferID: "checking"},
+			}},
+	}
+
+	months := MonthlyCashFlow(statements)
+	if len(months) != 2 {
+		t.Fatalf("expected 2 months, got %d", len(months))
+	}
+
+	march, april := months[0], months[1]
+	if march.Month != "2024-03" || march.Income != 4000 || march.Outflow != 0 || march.SavingsRate != 1 {
+		t.Errorf("unexpected march %+v", march)
+	}
+	if april.Month != "2024-04" || april.Income != 0 || april.Outflow != 1120 || april.Net != -1120 {
+		t.Errorf("unexpected april %+v", april)
+	}
+
+	// march ends with 5000 in checking and 300 owed on the card, april with 3800 and 220
+	if march.Position.Net != 4700 || april.Position.Net != 3580 {
+		t.Errorf("expected net positions 4700 and 3580, got %v and %v", march.Position.Net, april.Position.Net)
+	}
+
+	if len(april.Accounts) != 2 {
+		t.Fatalf("expected 2 accounts in april, got %d", len(april.Accounts))
+	}
+	card, checking := april.Accounts[0], april.Accounts[1]
+	if checking.Min != 3800 || checking.Max != 5000 || checking.Ending != 3800 {
+		t.Errorf("unexpected checking range %+v", checking)
+	}
+	if card.Min != 220 || card.Max != 450 || card.MaxDate != day(time.April, 5) {
+		t.Errorf("unexpected card range %+v", card)
+	}
+}
+
+func TestMonthlyCashFlow_cardPayment(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, time.April, d, 0, 0, 0, 0, time.UTC) }
+	// the checking account the card is paid from is not in the statements
+	statements := []model.Statement{
+		{Institution: model.BofA, AccountType: model.CreditCard, AccountNumber: "1234",
+			PeriodStartDate: day(1), PeriodEndDate: day(30), BeginningBalance: 500, EndingBalance: 70,
+			Transactions: []model.Transaction{
+				{PostingDate: day(5), Description: "PAYMENT - THANK YOU", Amount: -500, Category: "Payments and Other Credits"},
+				{PostingDate: day(8), Description: "COSTCO WHSE #1111 TOWN STATE", Amount: 100, Category: "Purchases and Adjustments"},
+				{PostingDate: day(12), Description: "COSTCO WHSE #1111 TOWN STATE", Amount: -30, Category: "Payments and Other Credits"},
+			}},
+	}
+
+	months := MonthlyCashFlow(statements)
+	if len(months) != 1 {
+		t.Fatalf("expected 1 month, got %d", len(months))
+	}
+	if months[0].Income != 0 || months[0].Outflow != 70 || months[0].Net != -70 {
+		t.Errorf("expected the outflow of 70 without the payment, got %+v", months[0])
+	}
+}
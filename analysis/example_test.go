@@ -0,0 +1,99 @@
+package analysis
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/muly/bank-tx/model"
+	"github.com/muly/bank-tx/td"
+)
+
+// history returns six monthly statements of a checking account and a credit card
+func history() []model.Statement {
+	day := func(month time.Month, d int) time.Time { return time.Date(2024, month, d, 0, 0, 0, 0, time.UTC) }
+
+	// the costco trips are not on a schedule
+	costco := []int{3, 9, 21, 8, 27, 14}
+
+	var statements []model.Statement
+	for month := time.January; month <= time.June; month++ {
+		checking := model.Statement{Institution: model.TD, AccountType: model.Checking, AccountNumber: "123-4567890",
+			PeriodStartDate: day(month, 1), PeriodEndDate: day(month+1, 0)}
+		card := model.Statement{Institution: model.BofA, AccountType: model.CreditCard, AccountNumber: "1234",
+			PeriodStartDate: day(month, 1), PeriodEndDate: day(month+1, 0)}
+
+		checking.Transactions = append(checking.Transactions,
+			model.Transaction{PostingDate: day(month, 2), Description: "ELECTRONIC PMT-WEB, EEERERERERE MTG PAYMENTS ****311244", Amount: -6208.46},
+			model.Transaction{PostingDate: day(month, 3), Description: "ACH DEPOSIT, ACME CORP PAYROLL", Amount: 6377.30},
+			model.Transaction{PostingDate: day(month, 17), Description: "ACH DEPOSIT, ACME CORP PAYROLL", Amount: 6377.30},
+			model.Transaction{PostingDate: day(month, 20), Description: "TD BILL PAY SERV, BANK OF AMERICA ONLINE PMT TDB****34454454", Amount: -500, TransferID: "x"},
+		)
+
+		netflix := 15.49
+		if month >= time.April {
+			netflix = 17.99
+		}
+		card.Transactions = append(card.Transactions,
+			model.Transaction{TransactionDate: day(month, 12), PostingDate: day(month, 13), Description: "NETFLIX.COM 866-579-7172 CA", Amount: netflix},
+			model.Transaction{TransactionDate: day(month, costco[month-1]), PostingDate: day(month, costco[month-1]+1), Description: "COSTCO WHSE #1111 TOWN STATE", Amount: float64(100 + month)},
+		)
+		if month != time.May {
+			card.Transactions = append(card.Transactions,
+				model.Transaction{TransactionDate: day(month, 5), PostingDate: day(month, 6), Description: "FITNESS CLUB 0042 TOWN ST", Amount: 40})
+		}
+		if month >= time.April {
+			card.Transactions = append(card.Transactions,
+				model.Transaction{TransactionDate: day(month, 21), PostingDate: day(month, 22), Description: "SP * STREAMBOX", Amount: 9.99})
+		}
+		statements = append(statements, checking, card)
+	}
+	return statements
+}
+
+func ExampleRecurring() {
+	for _, s := range Recurring(history(), Policy{}) {
+		fmt.Println(s)
+		for _, alert := range s.Alerts {
+			fmt.Printf("  %s %s: %s\n", alert.Date.Format("2006-01-02"), alert.Kind, alert.Message)
+		}
+	}
+
+	// Output:
+	// bofa 1234: monthly FITNESS CLUB 40.00, 5 times from 2024-01-05 to 2024-06-05, next on 2024-07-05
+	//   2024-05-05 missed: monthly FITNESS CLUB expected on 2024-05-05 did not happen
+	// bofa 1234: monthly NETFLIX.COM 16.74, 6 times from 2024-01-12 to 2024-06-12, next on 2024-07-12
+	//   2024-04-12 price_increase: NETFLIX.COM went up from 15.49 to 17.99 (+16.1%)
+	// bofa 1234: monthly STREAMBOX 9.99, 3 times from 2024-04-21 to 2024-06-21, next on 2024-07-21
+	//   2024-04-21 new: monthly STREAMBOX started on 2024-04-21
+	// td 123-4567890: biweekly ACH DEPOSIT, ACME CORP PAYROLL 6377.30, 12 times from 2024-01-03 to 2024-06-17, next on 2024-07-01
+	// td 123-4567890: monthly ELECTRONIC PMT-WEB, EEERERERERE MTG PAYMENTS 6208.46, 6 times from 2024-01-02 to 2024-06-02, next on 2024-07-02
+}
+
+func ExampleMonthlyCashFlow() {
+	data, err := os.ReadFile("../td/sample.txt")
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	s, err := td.ParseStatement(string(data))
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	for _, m := range MonthlyCashFlow([]model.Statement{s.ToModel()}) {
+		fmt.Printf("%s income %.2f outflow %.2f net %.2f savings %.1f%% position %.2f\n", m.Month, m.Income, m.Outflow, m.Net,
+			m.SavingsRate*100, m.Position.Net)
+		for _, a := range m.Accounts {
+			fmt.Printf("  %s %s min %.2f on %s, max %.2f on %s\n", a.Institution, a.AccountNumber,
+				a.Min, a.MinDate.Format("2006-01-02"), a.Max, a.MaxDate.Format("2006-01-02"))
+		}
+	}
+
+	// Output:
+	// 2023-03 income 418.00 outflow 5223.27 net -4805.27 savings -1149.6% position 5945.08
+	//   td 123-4567890 min 5945.08 on 2023-03-28, max 11168.35 on 2023-03-22
+	// 2023-04 income 16500.44 outflow 12314.45 net 4185.99 savings 25.4% position 10131.07
+	//   td 123-4567890 min 5945.08 on 2023-04-01, max 18699.68 on 2023-04-08
+}
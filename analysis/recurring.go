@@ -0,0 +1,289 @@
+package analysis
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/muly/bank-tx/merchant"
+	"github.com/muly/bank-tx/model"
+	"github.com/muly/bank-tx/util"
+)
+
+// Cadence is the period of the recurring transactions, Days is the typical number of the days between them and
+// Tolerance the number of the days they may be off
+type Cadence struct {
+	Name      string
+	Days      float64
+	Tolerance float64
+	// Months is the period in months for the monthly and longer cadences, the next date keeps the day of the month
+	// of the series
+	Months int
+}
+
+// Cadences are the cadences the series are matched to
+var (
+	Weekly    = Cadence{Name: "weekly", Days: 7, Tolerance: 1}
+	Biweekly  = Cadence{Name: "biweekly", Days: 14, Tolerance: 3}
+	Monthly   = Cadence{Name: "monthly", Days: 30.44, Tolerance: 4, Months: 1}
+	Quarterly = Cadence{Name: "quarterly", Days: 91.31, Tolerance: 8, Months: 3}
+	Yearly    = Cadence{Name: "yearly", Days: 365.25, Tolerance: 15, Months: 12}
+	Cadences  = []Cadence{Weekly, Biweekly, Monthly, Quarterly, Yearly}
+)
+
+// next returns the date one period after the date, the monthly and longer cadences fall on the day of the month,
+// or on the last day of the shorter months, e.g. the 31st is followed by Feb 28 then by Mar 31
+func (c Cadence) next(date time.Time, day int) time.Time {
+	if c.Months > 0 {
+		month := time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, date.Location()).AddDate(0, c.Months, 0)
+		if last := month.AddDate(0, 1, -1).Day(); day > last {
+			day = last
+		}
+		return month.AddDate(0, 0, day-1)
+	}
+	return date.AddDate(0, 0, int(c.Days))
+}
+
+// periods returns the number of the periods the days are, 0 when they are not a whole number of periods
+func (c Cadence) periods(days float64) int {
+	n := int(math.Round(days / c.Days))
+	if n < 1 || math.Abs(days-float64(n)*c.Days) > c.Tolerance*float64(n) {
+		return 0
+	}
+	return n
+}
+
+// Kinds of the alerts
+const (
+	// PriceIncrease is an amount higher than the earlier ones that the next occurrence keeps
+	PriceIncrease = "price_increase"
+	// Missed is an occurrence that did not happen when expected
+	Missed = "missed"
+	// New is a series that started recently
+	New = "new"
+)
+
+// Alert is a change in a recurring series
+type Alert struct {
+	Kind    string
+	Date    time.Time
+	Message string
+}
+
+// Occurrence is a transaction of a recurring series, Amount is its absolute amount
+type Occurrence struct {
+	ID     string
+	Date   time.Time
+	Amount float64
+}
+
+// Series is a recurring charge or deposit of an account
+type Series struct {
+	Merchant      string
+	Institution   string
+	AccountNumber string
+	Direction     string
+	Cadence       Cadence
+	// Amount is the typical absolute amount, the median of the occurrences
+	Amount      float64
+	Occurrences []Occurrence
+	// Next is the date the next occurrence is expected on
+	Next   time.Time
+	Alerts []Alert
+}
+
+func (s Series) String() string {
+	return fmt.Sprintf("%s %s: %s %s %.2f, %d times from %s to %s, next on %s", s.Institution, s.AccountNumber, s.Cadence.Name,
+		s.Merchant, s.Amount, len(s.Occurrences), s.Occurrences[0].Date.Format("2006-01-02"),
+		s.Occurrences[len(s.Occurrences)-1].Date.Format("2006-01-02"), s.Next.Format("2006-01-02"))
+}
+
+// Policy configures the detection, the zero values are replaced by the defaults
+type Policy struct {
+	// MinOccurrences is the number of the transactions a series needs, 3 by default
+	MinOccurrences int
+	// Regularity is the share of the intervals that must be whole periods of the cadence, 0.75 by default
+	Regularity float64
+	// PriceIncrease is the relative increase of the amount that is alerted, 0.02 by default
+	PriceIncrease float64
+	// NewPeriods is the number of the periods before the end of the history a new series starts within, 4 by default
+	NewPeriods int
+	// Normalizer groups the transactions by the canonical merchant, the merchants are parsed without the aliases when nil
+	Normalizer *merchant.Normalizer
+}
+
+func (p Policy) withDefaults() Policy {
+	if p.MinOccurrences == 0 {
+		p.MinOccurrences = 3
+	}
+	if p.Regularity == 0 {
+		p.Regularity = 0.75
+	}
+	if p.PriceIncrease == 0 {
+		p.PriceIncrease = 0.02
+	}
+	if p.NewPeriods == 0 {
+		p.NewPeriods = 4
+	}
+	return p
+}
+
+// Recurring finds the recurring transactions of the statements, grouped by the account, the direction and the merchant.
+// The transfers between the accounts are left out. The history of an account runs from the earliest start to the latest
+// end of its statement periods, the occurrences expected before its end that did not happen are alerted as missed.
+func Recurring(statements []model.Statement, p Policy) []Series {
+	p = p.withDefaults()
+
+	spans := map[string]*span{}
+	groups := map[string]*Series{}
+	var keys []string
+	for _, s := range statements {
+		account := s.Institution + "|" + s.AccountNumber
+		h, ok := spans[account]
+		if !ok {
+			h = &span{start: s.PeriodStartDate, end: s.PeriodEndDate}
+			spans[account] = h
+		}
+		if s.PeriodStartDate.Before(h.start) {
+			h.start = s.PeriodStartDate
+		}
+		if s.PeriodEndDate.After(h.end) {
+			h.end = s.PeriodEndDate
+		}
+
+		for _, tx := range s.Transactions {
+			if tx.TransferID != "" {
+				continue
+			}
+			name := p.Normalizer.Normalize(tx.Description).Merchant
+			key := account + "|" + s.Direction(tx) + "|" + name
+			g, ok := groups[key]
+			if !ok {
+				g = &Series{Merchant: name, Institution: s.Institution, AccountNumber: s.AccountNumber, Direction: s.Direction(tx)}
+				groups[key] = g
+				keys = append(keys, key)
+			}
+			g.Occurrences = append(g.Occurrences, Occurrence{ID: tx.ID, Date: date(tx), Amount: math.Abs(tx.Amount)})
+		}
+	}
+	sort.Strings(keys)
+
+	var series []Series
+	for _, key := range keys {
+		g := groups[key]
+		h := spans[g.Institution+"|"+g.AccountNumber]
+		if s, ok := detect(*g, h.start, h.end, p); ok {
+			series = append(series, s)
+		}
+	}
+	return series
+}
+
+// span is the history of an account, the time covered by its statements
+type span struct {
+	start, end time.Time
+}
+
+// detect matches the occurrences to a cadence and sets the typical amount, the next date and the alerts
+func detect(s Series, start, end time.Time, p Policy) (Series, bool) {
+	if len(s.Occurrences) < p.MinOccurrences {
+		return s, false
+	}
+	sort.SliceStable(s.Occurrences, func(i, j int) bool {
+		return s.Occurrences[i].Date.Before(s.Occurrences[j].Date)
+	})
+
+	intervals := make([]float64, 0, len(s.Occurrences)-1)
+	for i := 1; i < len(s.Occurrences); i++ {
+		intervals = append(intervals, s.Occurrences[i].Date.Sub(s.Occurrences[i-1].Date).Hours()/24)
+	}
+
+	// the cadence of the shortest regular interval, the longer ones are the missed occurrences
+	found := false
+	for _, c := range Cadences {
+		if c.periods(median(intervals)) == 1 {
+			s.Cadence, found = c, true
+			break
+		}
+	}
+	if !found {
+		return s, false
+	}
+
+	// the day of the month of the series, the occurrences moved to the next business day do not shift it
+	monthDays := make([]float64, 0, len(s.Occurrences))
+	for _, o := range s.Occurrences {
+		monthDays = append(monthDays, float64(o.Date.Day()))
+	}
+	day := int(math.Round(median(monthDays)))
+
+	regular := 0
+	for i, days := range intervals {
+		n := s.Cadence.periods(days)
+		if n == 0 {
+			continue
+		}
+		regular++
+		expected := s.Occurrences[i].Date
+		for k := 1; k < n; k++ {
+			expected = s.Cadence.next(expected, day)
+			s.Alerts = append(s.Alerts, Alert{Kind: Missed, Date: expected,
+				Message: fmt.Sprintf("%s %s expected on %s did not happen", s.Cadence.Name, s.Merchant, expected.Format("2006-01-02"))})
+		}
+	}
+	if float64(regular) < p.Regularity*float64(len(intervals)) {
+		return s, false
+	}
+
+	amounts := make([]float64, 0, len(s.Occurrences))
+	for _, o := range s.Occurrences {
+		amounts = append(amounts, o.Amount)
+	}
+	// the amount goes up against the median of the earlier occurrences, so a prorated first charge is not the baseline, and
+	// stays up on the next occurrence, so a one off charge is not an increase; the occurrences after it are the same increase
+	for i := 2; i+1 < len(amounts); i++ {
+		baseline := median(amounts[:i])
+		limit := baseline * (1 + p.PriceIncrease)
+		if amounts[i] > limit && amounts[i+1] > limit && amounts[i-1] <= limit {
+			s.Alerts = append(s.Alerts, Alert{Kind: PriceIncrease, Date: s.Occurrences[i].Date,
+				Message: fmt.Sprintf("%s went up from %.2f to %.2f (%+.1f%%)", s.Merchant, baseline, amounts[i], (amounts[i]/baseline-1)*100)})
+		}
+	}
+	s.Amount = util.RoundToTwoDecimal(median(amounts))
+
+	last := s.Occurrences[len(s.Occurrences)-1].Date
+	s.Next = s.Cadence.next(last, day)
+	for !s.Next.AddDate(0, 0, int(s.Cadence.Tolerance)).After(end) {
+		s.Alerts = append(s.Alerts, Alert{Kind: Missed, Date: s.Next,
+			Message: fmt.Sprintf("%s %s expected on %s did not happen", s.Cadence.Name, s.Merchant, s.Next.Format("2006-01-02"))})
+		s.Next = s.Cadence.next(s.Next, day)
+	}
+
+	// a series is new when it could have been seen a period earlier but started within the last periods
+	first := s.Occurrences[0].Date
+	if first.Sub(start).Hours()/24 > s.Cadence.Days+s.Cadence.Tolerance &&
+		end.Sub(first).Hours()/24 < float64(p.NewPeriods)*s.Cadence.Days {
+		s.Alerts = append(s.Alerts, Alert{Kind: New, Date: first,
+			Message: fmt.Sprintf("%s %s started on %s", s.Cadence.Name, s.Merchant, first.Format("2006-01-02"))})
+	}
+
+	sort.SliceStable(s.Alerts, func(i, j int) bool {
+		return s.Alerts[i].Date.Before(s.Alerts[j].Date)
+	})
+	return s, true
+}
+
+// median returns the median of the values
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	n := len(sorted)
+	if n%2 == 1 {
+		return sorted[n/2]
+	}
+	return (sorted[n/2-1] + sorted[n/2]) / 2
+}
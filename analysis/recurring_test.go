@@ -0,0 +1,188 @@
+package analysis
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/muly/bank-tx/model"
+)
+
+func TestCadencePeriods(t *testing.T) {
+	tests := []struct {
+		cadence  Cadence
+		days     float64
+		expected int
+	}{
+		{Weekly, 7, 1},
+		{Weekly, 9, 0},
+		{Biweekly, 15, 1},
+		{Monthly, 28, 1},
+		{Monthly, 31, 1},
+		{Monthly, 61, 2},
+		{Monthly, 45, 0},
+		{Quarterly, 92, 1},
+		{Yearly, 366, 1},
+		{Yearly, 200, 0},
+	}
+
+	for _, tt := range tests {
+		if actual := tt.cadence.periods(tt.days); actual != tt.expected {
+			t.Errorf("%s %v days: expected %d periods, got %d", tt.cadence.Name, tt.days, tt.expected, actual)
+		}
+	}
+}
+
+func TestRecurringStopped(t *testing.T) {
+	day := func(month time.Month, d int) time.Time { return time.Date(2024, month, d, 0, 0, 0, 0, time.UTC) }
+	s := model.Statement{Institution: model.BofA, AccountType: model.CreditCard, AccountNumber: "1234",
+		PeriodStartDate: day(time.January, 1), PeriodEndDate: day(time.June, 30)}
+	for month := time.January; month <= time.March; month++ {
+		s.Transactions = append(s.Transactions, model.Transaction{PostingDate: day(month, 10), Description: "MAGAZINE SUBSCRIPTION", Amount: 5})
+	}
+
+	series := Recurring([]model.Statement{s}, Policy{})
+	if len(series) != 1 {
+		t.Fatalf("expected 1 series, got %d", len(series))
+	}
+	if series[0].Direction != model.Outflow {
+		t.Errorf("expected %s, got %s", model.Outflow, series[0].Direction)
+	}
+
+	var missed []string
+	for _, alert := range series[0].Alerts {
+		if alert.Kind == Missed {
+			missed = append(missed, alert.Date.Format("2006-01-02"))
+		}
+	}
+	expected := []string{"2024-04-10", "2024-05-10", "2024-06-10"}
+	if len(missed) != len(expected) {
+		t.Fatalf("expected missed %v, got %v", expected, missed)
+	}
+	for i := range expected {
+		if missed[i] != expected[i] {
+			t.Errorf("expected missed %v, got %v", expected, missed)
+		}
+	}
+	if next := series[0].Next.Format("2006-01-02"); next != "2024-07-10" {
+		t.Errorf("expected next 2024-07-10, got %s", next)
+	}
+
+	if series := Recurring([]model.Statement{s}, Policy{MinOccurrences: 4}); len(series) != 0 {
+		t.Errorf("expected no series with 4 occurrences required, got %d", len(series))
+	}
+}
+
+func TestCadenceNext(t *testing.T) {
+	day := func(year int, month time.Month, d int) time.Time {
+		return time.Date(year, month, d, 0, 0, 0, 0, time.UTC)
+	}
+	tests := []struct {
+		cadence  Cadence
+		date     time.Time
+		day      int
+		expected time.Time
+	}{
+		{Monthly, day(2024, time.January, 31), 31, day(2024, time.February, 29)},
+		{Monthly, day(2024, time.February, 29), 31, day(2024, time.March, 31)},
+		{Monthly, day(2024, time.March, 31), 31, day(2024, time.April, 30)},
+		{Monthly, day(2024, time.April, 30), 31, day(2024, time.May, 31)},
+		{Monthly, day(2024, time.December, 31), 31, day(2025, time.January, 31)},
+		{Monthly, day(2024, time.March, 18), 15, day(2024, time.April, 15)},
+		{Quarterly, day(2024, time.November, 30), 31, day(2025, time.February, 28)},
+		{Yearly, day(2024, time.February, 29), 29, day(2025, time.February, 28)},
+		{Biweekly, day(2024, time.January, 31), 31, day(2024, time.February, 14)},
+	}
+
+	for _, tt := range tests {
+		if actual := tt.cadence.next(tt.date, tt.day); !actual.Equal(tt.expected) {
+			t.Errorf("%s after %s on the %d: expected %s, got %s", tt.cadence.Name, tt.date.Format("2006-01-02"), tt.day,
+				tt.expected.Format("2006-01-02"), actual.Format("2006-01-02"))
+		}
+	}
+}
+
+func TestRecurringMonthEnd(t *testing.T) {
+	day := func(month time.Month, d int) time.Time { return time.Date(2024, month, d, 0, 0, 0, 0, time.UTC) }
+	s := model.Statement{Institution: model.TD, AccountType: model.Checking, AccountNumber: "123-4567890",
+		PeriodStartDate: day(time.January, 1), PeriodEndDate: day(time.June, 20)}
+	for _, d := range []time.Time{day(time.January, 31), day(time.February, 29), day(time.March, 31), day(time.April, 30), day(time.May, 31)} {
+		s.Transactions = append(s.Transactions, model.Transaction{PostingDate: d, Description: "EEERERERERE MTG PAYMENTS", Amount: -2500})
+	}
+
+	series := Recurring([]model.Statement{s}, Policy{})
+	if len(series) != 1 {
+		t.Fatalf("expected 1 series, got %d", len(series))
+	}
+	if len(series[0].Alerts) != 0 {
+		t.Errorf("expected no alerts, got %+v", series[0].Alerts)
+	}
+	if next := series[0].Next.Format("2006-01-02"); next != "2024-06-30" {
+		t.Errorf("expected next 2024-06-30, got %s", next)
+	}
+}
+
+func TestRecurringPriceIncrease(t *testing.T) {
+	day := func(month time.Month) time.Time { return time.Date(2024, month, 12, 0, 0, 0, 0, time.UTC) }
+
+	tests := []struct {
+		name     string
+		amounts  []float64
+		expected []string
+	}{
+		{"case 1: prorated first month", []float64{7.50, 15.49, 15.49, 15.49, 15.49}, nil},
+		{"case 2: increase kept", []float64{15.49, 15.49, 15.49, 17.99, 17.99, 17.99}, []string{"2024-04-12"}},
+		{"case 3: one off charge", []float64{15.49, 15.49, 15.49, 22.99, 15.49, 15.49}, nil},
+		{"case 4: increase on the last occurrence", []float64{15.49, 15.49, 15.49, 15.49, 17.99}, nil},
+		{"case 5: two increases", []float64{10, 10, 10, 12, 12, 12, 12, 14, 14}, []string{"2024-04-12", "2024-08-12"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := model.Statement{Institution: model.BofA, AccountType: model.CreditCard, AccountNumber: "1234",
+				PeriodStartDate: day(time.January), PeriodEndDate: day(time.Month(len(tt.amounts)))}
+			for i, amount := range tt.amounts {
+				s.Transactions = append(s.Transactions, model.Transaction{PostingDate: day(time.Month(i + 1)), Description: "NETFLIX.COM", Amount: amount})
+			}
+
+			series := Recurring([]model.Statement{s}, Policy{})
+			if len(series) != 1 {
+				t.Fatalf("expected 1 series, got %d", len(series))
+			}
+			var increases []string
+			for _, alert := range series[0].Alerts {
+				if alert.Kind == PriceIncrease {
+					increases = append(increases, alert.Date.Format("2006-01-02"))
+				}
+			}
+			if !reflect.DeepEqual(increases, tt.expected) {
+				t.Errorf("expected the price increases %v, got %v", tt.expected, increases)
+			}
+		})
+	}
+}
+
+func TestRecurringAccountHistory(t *testing.T) {
+	day := func(month time.Month, d int) time.Time { return time.Date(2024, month, d, 0, 0, 0, 0, time.UTC) }
+	// the closed card has statements until june, the new card from september
+	closed := model.Statement{Institution: model.BofA, AccountType: model.CreditCard, AccountNumber: "1234",
+		PeriodStartDate: day(time.January, 1), PeriodEndDate: day(time.June, 30)}
+	opened := model.Statement{Institution: model.Chase, AccountType: model.CreditCard, AccountNumber: "5678",
+		PeriodStartDate: day(time.September, 1), PeriodEndDate: day(time.December, 20)}
+	for month := time.January; month <= time.June; month++ {
+		closed.Transactions = append(closed.Transactions, model.Transaction{PostingDate: day(month, 10), Description: "NETFLIX.COM", Amount: 15.49})
+	}
+	for month := time.September; month <= time.December; month++ {
+		opened.Transactions = append(opened.Transactions, model.Transaction{PostingDate: day(month, 5), Description: "NETFLIX.COM", Amount: 15.49})
+	}
+
+	series := Recurring([]model.Statement{closed, opened}, Policy{})
+	if len(series) != 2 {
+		t.Fatalf("expected 2 series, got %d", len(series))
+	}
+	for _, s := range series {
+		if len(s.Alerts) != 0 {
+			t.Errorf("%s %s: expected no alerts within the history of the account, got %+v", s.Institution, s.AccountNumber, s.Alerts)
+		}
+	}
+}
@@ -2,7 +2,6 @@
 package bofa_cc
 
 import (
-	"encoding/csv"
 	"fmt"
 	"log"
 	"os"
@@ -10,10 +9,12 @@ import (
 	"strings"
 	"time"
 
+	"github.com/muly/bank-tx/csvexport"
+	"github.com/muly/bank-tx/model"
 	"github.com/muly/bank-tx/util"
 )
 
-// TODO: interest lines are not included in the parsing logic.
+var rePeriod = regexp.MustCompile(`^[A-Z][a-z]+ \d{1,2} - [A-Z][a-z]+ \d{1,2}, \d{4}$`)
 
 type Transaction struct {
 	TransactionDate time.Time
@@ -59,7 +60,7 @@ func ParseStatement(data string) (*Statement, error) {
 		}
 
 		// Parse Statement Period
-		if strings.Contains(line, "-") && strings.HasSuffix(line, "2023") { // TODO: need to replace this with a regex
+		if rePeriod.MatchString(line) {
 			periodStartDate, periodEndDate, err = parseStatementPeriod(line)
 			if err != nil {
 				return nil, err
@@ -251,70 +252,57 @@ func ParseTransaction(line string, startPeriod, endPeriod time.Time) (*Transacti
 	return nil, nil
 }
 
-// SaveTransactionsToCSV writes transactions to CSV
+// SaveTransactionsToCSV writes the transactions of the statement to a csv file in the current directory,
+// named by csvexport.DefaultNameTemplate
 func SaveTransactionsToCSV(statement Statement) error {
-	filename := fmt.Sprintf("%s|%s_to_%s.csv", statement.AccountNumber,
-		statement.PeriodStartDate.Format("Jan_02_2006"),
-		statement.PeriodEndDate.Format("Jan_02_2006"))
-	file, err := os.Create(filename)
-	if err != nil {
-		return err
-	}
-	defer file.Close()
-
-	writer := csv.NewWriter(file)
-	defer writer.Flush()
-
-	// Write CSV headers
-	writer.Write([]string{"TransactionDate", "PostingDate", "Description", "ReferenceNumber", "AccountNumber", "Amount", "Category", "StatementAccountNumber", "StatementPeriod"})
-
-	// Write transaction data
-	for _, tx := range statement.Transactions {
-		writer.Write([]string{
-			tx.TransactionDate.Format("01/02/2006"),
-			tx.PostingDate.Format("01/02/2006"),
-			tx.Description,
-			tx.ReferenceNumber,
-			tx.AccountNumber,
-			fmt.Sprintf("%.2f", tx.Amount),
-			tx.Category,
-			statement.AccountNumber,
-			fmt.Sprintf("%s-%s", statement.PeriodStartDate.Format("2006-01-02"), statement.PeriodEndDate.Format("2006-01-02")),
-		})
-	}
-
-	return nil
+	_, err := csvexport.WriteFiles(".", csvexport.DefaultNameTemplate, []model.Statement{statement.ToModel()}, csvexport.Options{})
+	return err
 }
 
+// SaveStatementsToCSV writes the transactions of all the statements to the csv file
 func SaveStatementsToCSV(statements []Statement, filename string) error {
 	file, err := os.Create(filename)
 	if err != nil {
 		return err
 	}
-	defer file.Close()
 
-	writer := csv.NewWriter(file)
-	defer writer.Flush()
+	converted := make([]model.Statement, 0, len(statements))
+	for _, statement := range statements {
+		converted = append(converted, statement.ToModel())
+	}
 
-	writer.Write([]string{"TransactionDate", "PostingDate", "Description", "ReferenceNumber", "AccountNumber", "Amount", "Category", "StatementAccountNumber", "StatementPeriod"})
+	if err := csvexport.Write(file, converted, csvexport.Options{}); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
 
-	for _, statement := range statements {
-		for _, tx := range statement.Transactions {
-			writer.Write([]string{
-				tx.TransactionDate.Format("01/02/2006"),
-				tx.PostingDate.Format("01/02/2006"),
-				tx.Description,
-				tx.ReferenceNumber,
-				tx.AccountNumber,
-				fmt.Sprintf("%.2f", tx.Amount),
-				tx.Category,
-				statement.AccountNumber,
-				fmt.Sprintf("%s-%s", statement.PeriodStartDate.Format("2006-01-02"), statement.PeriodEndDate.Format("2006-01-02")),
-			})
-		}
+// ToModel converts the statement to the bank neutral model
+func (s Statement) ToModel() model.Statement {
+	transactions := make([]model.Transaction, 0, len(s.Transactions))
+	for _, tx := range s.Transactions {
+		transactions = append(transactions, model.Transaction{
+			TransactionDate: tx.TransactionDate,
+			PostingDate:     tx.PostingDate,
+			Description:     tx.Description,
+			ReferenceNumber: tx.ReferenceNumber,
+			AccountNumber:   tx.AccountNumber,
+			Amount:          tx.Amount,
+			Category:        tx.Category,
+		})
 	}
 
-	return nil
+	return model.Statement{
+		Institution:      model.BofA,
+		AccountType:      model.CreditCard,
+		AccountNumber:    s.AccountNumber,
+		PeriodStartDate:  s.PeriodStartDate,
+		PeriodEndDate:    s.PeriodEndDate,
+		BeginningBalance: s.BeginningBalance,
+		EndingBalance:    s.EndingBalance,
+		Transactions:     transactions,
+	}
 }
 
 // Helper functions
@@ -358,22 +346,7 @@ func parseStatementPeriod(periodStr string) (time.Time, time.Time, error) {
 
 // addYearToDate adds the correct year to a given month-day date based on the statement period
 func addYearToDate(dateStr string, startPeriod, endPeriod time.Time) (time.Time, error) {
-	parsedDate, err := time.Parse("01/02", dateStr)
-	if err != nil {
-		return time.Time{}, err
-	}
-
-	// Set the year based on start and end periods
-	year := endPeriod.Year()
-
-	// For cross-year periods, dates before the end month should use startPeriod's year
-	if startPeriod.Year() != endPeriod.Year() {
-		if parsedDate.Month() > endPeriod.Month() {
-			year = startPeriod.Year()
-		}
-	}
-
-	return parsedDate.AddDate(year, 0, 0), nil
+	return util.AddYearToDate(dateStr, "01/02", startPeriod, endPeriod)
 }
 
 func calculateTotal(transactions []Transaction) float64 {
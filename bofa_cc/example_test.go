@@ -93,4 +93,8 @@ TOTAL INTEREST CHARGED FOR THIS PERIOD $0.00
 	// {TransactionDate:2024-10-05 00:00:00 +0000 UTC PostingDate:2024-10-07 00:00:00 +0000 UTC Description:MY CHURCH EWWEW WEWEWE ReferenceNumber:5336 AccountNumber:1234 Amount:10 Category:Purchases and Adjustments}
 	// {TransactionDate:2024-10-06 00:00:00 +0000 UTC PostingDate:2024-10-07 00:00:00 +0000 UTC Description:DUNKIN #111111 TOWN STATE ReferenceNumber:3379 AccountNumber:1234 Amount:3.64 Category:Purchases and Adjustments}
 	// {TransactionDate:2024-10-11 00:00:00 +0000 UTC PostingDate:2024-10-11 00:00:00 +0000 UTC Description:SP HAIR HTTPSWWW.HAIR ReferenceNumber:0637 AccountNumber:1234 Amount:106.43 Category:Purchases and Adjustments}
+	// {TransactionDate:2024-10-11 00:00:00 +0000 UTC PostingDate:2024-10-11 00:00:00 +0000 UTC Description:INTEREST CHARGED ON PURCHASES ReferenceNumber: AccountNumber: Amount:0 Category:Interest Charged}
+	// {TransactionDate:2024-10-11 00:00:00 +0000 UTC PostingDate:2024-10-11 00:00:00 +0000 UTC Description:INTEREST CHARGED ON BALANCE TRANSFERS ReferenceNumber: AccountNumber: Amount:0 Category:Interest Charged}
+	// {TransactionDate:2024-10-11 00:00:00 +0000 UTC PostingDate:2024-10-11 00:00:00 +0000 UTC Description:INTEREST CHARGED ON DIR DEP&CHK CASHADV ReferenceNumber: AccountNumber: Amount:0 Category:Interest Charged}
+	// {TransactionDate:2024-10-11 00:00:00 +0000 UTC PostingDate:2024-10-11 00:00:00 +0000 UTC Description:INTEREST CHARGED ON BANK CASH ADVANCES ReferenceNumber: AccountNumber: Amount:0 Category:Interest Charged}
 }
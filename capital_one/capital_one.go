@@ -0,0 +1,224 @@
+// package capital_one provides the parsing functions to process the capital one credit card statements
+package capital_one
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/muly/bank-tx/model"
+	"github.com/muly/bank-tx/util"
+)
+
+type Transaction struct {
+	TransactionDate time.Time
+	PostingDate     time.Time
+	Description     string
+	Amount          float64
+	Category        string
+}
+
+type Statement struct {
+	AccountNumber    string
+	PeriodStartDate  time.Time
+	PeriodEndDate    time.Time
+	BeginningBalance float64
+	EndingBalance    float64
+	Transactions     []Transaction
+}
+
+var (
+	reAccount     = regexp.MustCompile(`ending in (\d{4})$`)
+	rePeriod      = regexp.MustCompile(`^([A-Z][a-z]{2} \d{1,2}, \d{4}) - ([A-Z][a-z]{2} \d{1,2}, \d{4})`)
+	reSummary     = regexp.MustCompile(`^(Previous Balance|Payments|Other Credits|Transactions|Cash Advances|Fees Charged|Interest Charged|New Balance) ([-+=]?\s?\$[\d,]+\.\d{2})$`)
+	reTransaction = regexp.MustCompile(`^([A-Z][a-z]{2} \d{1,2})\s+([A-Z][a-z]{2} \d{1,2})\s+(.+?)\s+(-?\s?\$[\d,]+\.\d{2})$`)
+	reInterest    = regexp.MustCompile(`^(Interest Charge on .+?)\s+(\$[\d,]+\.\d{2})$`)
+)
+
+// ParseStatement parses the statement
+func ParseStatement(data string) (*Statement, error) {
+	lines := strings.Split(data, "\n")
+
+	var statement Statement
+	summary := map[string]float64{}
+	inCategory := ""
+	var err error
+
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+
+		if line == "" {
+			continue
+		}
+
+		// Parse Statement Period
+		if match := rePeriod.FindStringSubmatch(line); match != nil {
+			statement.PeriodStartDate, statement.PeriodEndDate, err = parseStatementPeriod(match[1], match[2])
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		// Parse Account Number, it is repeated above the transactions of the card
+		if match := reAccount.FindStringSubmatch(line); match != nil {
+			statement.AccountNumber = match[1]
+			continue
+		}
+
+		// Parse the account summary
+		if match := reSummary.FindStringSubmatch(line); match != nil {
+			summary[match[1]], err = parseAmount(match[2])
+			if err != nil {
+				return nil, fmt.Errorf("%s parse error: %v", match[1], err)
+			}
+			continue
+		}
+
+		if line == "Payments, Credits and Adjustments" ||
+			line == "Transactions" ||
+			line == "Fees" ||
+			line == "Interest Charged" {
+			inCategory = line
+			continue // Skip the header line
+		}
+
+		if line == "Trans Date Post Date Description Amount" ||
+			line == "Account Summary" {
+			continue // Skip the header line
+		}
+
+		if strings.HasPrefix(line, "Total ") {
+			continue // Skip the transaction subtotal line
+		}
+
+		transaction, err := ParseTransaction(line, statement.PeriodStartDate, statement.PeriodEndDate)
+		if err != nil {
+			return nil, err
+		}
+		if transaction == nil {
+			log.Printf("unprocessed line: %v", line)
+			continue // unwanted line
+		}
+
+		transaction.Category = inCategory
+
+		statement.Transactions = append(statement.Transactions, *transaction)
+	}
+
+	statement.BeginningBalance = summary["Previous Balance"]
+	statement.EndingBalance = summary["New Balance"]
+
+	// Validate balances
+	calculatedEndBalance := summary["Previous Balance"] + summary["Payments"] + summary["Other Credits"] + summary["Transactions"] +
+		summary["Cash Advances"] + summary["Fees Charged"] + summary["Interest Charged"]
+	if util.RoundToTwoDecimal(calculatedEndBalance) != util.RoundToTwoDecimal(statement.EndingBalance) {
+		return nil, fmt.Errorf("summary balance validation failed: calculated new balance %.2f, expected new balance %.2f", calculatedEndBalance, statement.EndingBalance)
+	}
+
+	total := calculateTotal(statement.Transactions)
+	if util.RoundToTwoDecimal(statement.BeginningBalance+total) != util.RoundToTwoDecimal(statement.EndingBalance) {
+		return nil, fmt.Errorf("tx balance validation failed. calculated end balance %v, expected end balance %v", util.RoundToTwoDecimal(statement.BeginningBalance+total), statement.EndingBalance)
+	}
+
+	return &statement, nil
+}
+
+// ParseTransaction parses the given transaction entry
+func ParseTransaction(line string, startPeriod, endPeriod time.Time) (*Transaction, error) {
+	if matches := reTransaction.FindStringSubmatch(line); matches != nil {
+		amount, err := parseAmount(matches[4])
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse amount: %s, error: %v", matches[4], err)
+		}
+
+		transaction := Transaction{
+			Description: matches[3],
+			Amount:      amount,
+		}
+
+		transaction.TransactionDate, err = util.AddYearToDate(matches[1], "Jan 2", startPeriod, endPeriod)
+		if err != nil {
+			return nil, fmt.Errorf("error adding year to transaction date: %v", err)
+		}
+		transaction.PostingDate, err = util.AddYearToDate(matches[2], "Jan 2", startPeriod, endPeriod)
+		if err != nil {
+			return nil, fmt.Errorf("error adding year to posting date: %v", err)
+		}
+
+		return &transaction, nil
+	}
+
+	// Interest lines carry no dates, they are charged on the closing date
+	if matches := reInterest.FindStringSubmatch(line); matches != nil {
+		amount, err := parseAmount(matches[2])
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse amount: %s, error: %v", matches[2], err)
+		}
+
+		return &Transaction{
+			TransactionDate: endPeriod,
+			PostingDate:     endPeriod,
+			Description:     matches[1],
+			Amount:          amount,
+		}, nil
+	}
+
+	return nil, nil
+}
+
+// ToModel converts the statement to the bank neutral model
+func (s Statement) ToModel() model.Statement {
+	transactions := make([]model.Transaction, 0, len(s.Transactions))
+	for _, tx := range s.Transactions {
+		transactions = append(transactions, model.Transaction{
+			TransactionDate: tx.TransactionDate,
+			PostingDate:     tx.PostingDate,
+			Description:     tx.Description,
+			Amount:          tx.Amount,
+			Category:        tx.Category,
+		})
+	}
+
+	return model.Statement{
+		Institution:      model.CapitalOne,
+		AccountType:      model.CreditCard,
+		AccountNumber:    s.AccountNumber,
+		PeriodStartDate:  s.PeriodStartDate,
+		PeriodEndDate:    s.PeriodEndDate,
+		BeginningBalance: s.BeginningBalance,
+		EndingBalance:    s.EndingBalance,
+		Transactions:     transactions,
+	}
+}
+
+// Helper functions
+
+func parseStatementPeriod(start, end string) (time.Time, time.Time, error) {
+	startPeriod, err := time.Parse("Jan 2, 2006", start)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("failed to parse start period: %v", err)
+	}
+	endPeriod, err := time.Parse("Jan 2, 2006", end)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("failed to parse end period: %v", err)
+	}
+	return startPeriod, endPeriod, nil
+}
+
+// parseAmount parses the amounts like "- $1,234.56", "+ $0.00" and "= $196.95"
+func parseAmount(s string) (float64, error) {
+	s = strings.ReplaceAll(s, " ", "")
+	s = strings.TrimPrefix(s, "=")
+	return util.ParseFloat(s)
+}
+
+func calculateTotal(transactions []Transaction) float64 {
+	total := 0.0
+	for _, tx := range transactions {
+		total += tx.Amount
+	}
+	return total
+}
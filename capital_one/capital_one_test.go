@@ -0,0 +1,56 @@
+package capital_one
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseTransaction(t *testing.T) {
+	start := time.Date(2023, 12, 15, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2024, 1, 14, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		line    string
+		want    *Transaction
+		wantErr bool
+	}{
+		{
+			name: "case 1: purchase across the year end",
+			line: "Dec 30 Jan 2 WHOLEFDS CAR 1111 TOWN ST $60.10",
+			want: &Transaction{
+				TransactionDate: time.Date(2023, 12, 30, 0, 0, 0, 0, time.UTC),
+				PostingDate:     time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+				Description:     "WHOLEFDS CAR 1111 TOWN ST",
+				Amount:          60.10,
+			},
+		},
+		{
+			name: "case 2: credit with spaced sign",
+			line: "Jan 2 Jan 2 CAPITAL ONE MOBILE PYMT - $1,234.56",
+			want: &Transaction{
+				TransactionDate: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+				PostingDate:     time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+				Description:     "CAPITAL ONE MOBILE PYMT",
+				Amount:          -1234.56,
+			},
+		},
+		{
+			name: "case 3: not a transaction",
+			line: "Trans Date Post Date Description Amount",
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseTransaction(tt.line, start, end)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseTransaction() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseTransaction() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package capital_one
+
+import (
+	"fmt"
+)
+
+func ExampleParseStatement() {
+	statement := `
+Capital One
+Quicksilver Card | Visa Signature ending in 1234
+Dec 15, 2023 - Jan 14, 2024 | 31 days in Billing Cycle
+
+Account Summary
+Previous Balance $1,234.56
+Payments - $1,234.56
+Other Credits - $25.00
+Transactions + $221.95
+Cash Advances + $0.00
+Fees Charged + $0.00
+Interest Charged + $0.00
+New Balance = $196.95
+
+Transactions
+Visa Signature ending in 1234
+Trans Date Post Date Description Amount
+Payments, Credits and Adjustments
+Jan 2 Jan 2 CAPITAL ONE MOBILE PYMT - $1,234.56
+Jan 3 Jan 4 THE HOME DEPOT #1111 TOWN ST - $25.00
+Transactions
+Dec 18 Dec 20 COSTCO WHSE #1111 TOWN ST $145.67
+Dec 30 Jan 2 WHOLEFDS CAR 1111 TOWN ST $60.10
+Jan 12 Jan 13 SQ *COFFEE SHOP TOWN ST $16.18
+Total Transactions for This Period $221.95
+Fees
+Total Fees for This Period $0.00
+Interest Charged
+Interest Charge on Purchases $0.00
+Interest Charge on Cash Advances $0.00
+Total Interest for This Period $0.00
+	`
+
+	s, err := ParseStatement(statement)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	for _, tx := range s.Transactions {
+		fmt.Printf("%+v\n", tx)
+	}
+
+	// Output:
+	// {TransactionDate:2024-01-02 00:00:00 +0000 UTC PostingDate:2024-01-02 00:00:00 +0000 UTC Description:CAPITAL ONE MOBILE PYMT Amount:-1234.56 Category:Payments, Credits and Adjustments}
+	// {TransactionDate:2024-01-03 00:00:00 +0000 UTC PostingDate:2024-01-04 00:00:00 +0000 UTC Description:THE HOME DEPOT #1111 TOWN ST Amount:-25 Category:Payments, Credits and Adjustments}
+	// {TransactionDate:2023-12-18 00:00:00 +0000 UTC PostingDate:2023-12-20 00:00:00 +0000 UTC Description:COSTCO WHSE #1111 TOWN ST Amount:145.67 Category:Transactions}
+	// {TransactionDate:2023-12-30 00:00:00 +0000 UTC PostingDate:2024-01-02 00:00:00 +0000 UTC Description:WHOLEFDS CAR 1111 TOWN ST Amount:60.1 Category:Transactions}
+	// {TransactionDate:2024-01-12 00:00:00 +0000 UTC PostingDate:2024-01-13 00:00:00 +0000 UTC Description:SQ *COFFEE SHOP TOWN ST Amount:16.18 Category:Transactions}
+	// {TransactionDate:2024-01-14 00:00:00 +0000 UTC PostingDate:2024-01-14 00:00:00 +0000 UTC Description:Interest Charge on Purchases Amount:0 Category:Interest Charged}
+	// {TransactionDate:2024-01-14 00:00:00 +0000 UTC PostingDate:2024-01-14 00:00:00 +0000 UTC Description:Interest Charge on Cash Advances Amount:0 Category:Interest Charged}
+}
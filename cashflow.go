@@ -0,0 +1,49 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/muly/bank-tx/analysis"
+	"github.com/muly/bank-tx/report"
+	"github.com/muly/bank-tx/store"
+)
+
+// runCashFlow writes the monthly cash flow, the net position and the balance range of every account of the store
+func runCashFlow(args []string) error {
+	flags := flag.NewFlagSet("cashflow", flag.ExitOnError)
+	db := flags.String("db", "bank-tx.db", "sqlite database file")
+	format := flags.String("format", report.Text, "output format: text, csv or markdown")
+	flags.Parse(args)
+
+	s, err := store.Open(*db)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+
+	statements, err := s.Statements()
+	if err != nil {
+		return err
+	}
+
+	flows := report.Table{Title: "Monthly cash flow",
+		Header: []string{"Month", "Income", "Outflow", "Net", "Savings rate", "Assets", "Liabilities", "Net position"}}
+	balances := report.Table{Title: "Monthly balances",
+		Header: []string{"Month", "Account", "Min", "Min date", "Max", "Max date", "Ending"}}
+	for _, m := range analysis.MonthlyCashFlow(statements) {
+		rate := ""
+		if m.Income > 0 {
+			rate = fmt.Sprintf("%.1f%%", m.SavingsRate*100)
+		}
+		flows.Rows = append(flows.Rows, []string{m.Month, report.Money(m.Income), report.Money(m.Outflow), report.Money(m.Net), rate,
+			report.Money(m.Position.Assets), report.Money(m.Position.Liabilities), report.Money(m.Position.Net)})
+
+		for _, a := range m.Accounts {
+			balances.Rows = append(balances.Rows, []string{m.Month, a.Institution + " " + a.AccountNumber,
+				report.Money(a.Min), a.MinDate.Format("2006-01-02"), report.Money(a.Max), a.MaxDate.Format("2006-01-02"), report.Money(a.Ending)})
+		}
+	}
+	return report.Write(os.Stdout, *format, flows, balances)
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/muly/bank-tx/categorize"
+	"github.com/muly/bank-tx/model"
+	"github.com/muly/bank-tx/store"
+)
+
+// runCategorize sets the spending category of the uncategorized transactions in the store by the rules file, or the category
+// of a single transaction by hand with -id, the categories set by hand are the ones the classifier is trained on
+func runCategorize(args []string) error {
+	flags := flag.NewFlagSet("categorize", flag.ExitOnError)
+	db := flags.String("db", "bank-tx.db", "sqlite database file")
+	rules := flags.String("rules", "rules.yaml", "yaml or json rules file")
+	dryRun := flags.Bool("dry-run", false, "print the categories without saving them")
+	id := flags.String("id", "", "id of the transaction to set the -category of by hand, as printed by categorize and suggest")
+	category := flags.String("category", "", "spending category set by hand with -id, empty to clear it")
+	flags.Parse(args)
+
+	if *id != "" {
+		return setCategory(*db, *id, *category)
+	}
+
+	engine, err := categorize.Load(*rules)
+	if err != nil {
+		return err
+	}
+
+	s, err := store.Open(*db)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+
+	statements, err := s.Statements()
+	if err != nil {
+		return err
+	}
+
+	results := engine.Apply(statements)
+	for _, statement := range statements {
+		for _, tx := range statement.Transactions {
+			result, ok := results[tx.ID]
+			if !ok {
+				continue
+			}
+			fmt.Printf("%s %s %s %.2f => %s, %s\n", tx.ID, tx.PostingDate.Format("2006-01-02"), tx.Description, tx.Amount, result.Category, result.Explanation)
+			if *dryRun {
+				continue
+			}
+			if _, err := s.SetSpendingCategory(tx.ID, result.Category, model.SourceRules); err != nil {
+				return err
+			}
+		}
+	}
+	fmt.Printf("%d transactions categorized\n", len(results))
+	return nil
+}
+
+// setCategory sets the spending category of the transaction by hand
+func setCategory(db, id, category string) error {
+	if category != "" {
+		if err := categorize.ValidateCategory(category); err != nil {
+			return err
+		}
+	}
+
+	s, err := store.Open(db)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+
+	updated, err := s.SetSpendingCategory(id, category, model.SourceManual)
+	if err != nil {
+		return err
+	}
+	if updated == 0 {
+		return fmt.Errorf("no transaction %s in %s", id, db)
+	}
+	fmt.Printf("%s => %q\n", id, category)
+	return nil
+}
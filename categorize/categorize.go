@@ -0,0 +1,256 @@
+// package categorize provides the rules engine that sets the spending category of the transactions, what the money was
+// spent on, from a yaml or json rules file
+package categorize
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/muly/bank-tx/model"
+	"github.com/muly/bank-tx/util"
+)
+
+// Range is an inclusive range of the amounts, a zero bound is not checked
+type Range struct {
+	Min float64 `yaml:"min" json:"min"`
+	Max float64 `yaml:"max" json:"max"`
+}
+
+func (r Range) String() string {
+	switch {
+	case r.Min != 0 && r.Max != 0:
+		return fmt.Sprintf("between %.2f and %.2f", r.Min, r.Max)
+	case r.Min != 0:
+		return fmt.Sprintf("at least %.2f", r.Min)
+	case r.Max != 0:
+		return fmt.Sprintf("at most %.2f", r.Max)
+	}
+	return "any"
+}
+
+// Rule maps the transactions matching all of its conditions to a category, the empty conditions are not checked
+type Rule struct {
+	Name     string `yaml:"name" json:"name"`
+	Category string `yaml:"category" json:"category"`
+	// Priority orders the rules, the higher priority rules are tried first and the ties in the order of the file
+	Priority int `yaml:"priority" json:"priority"`
+
+	// Description is a case insensitive regexp of the transaction description
+	Description string `yaml:"description" json:"description"`
+	// Amount is the range of the absolute amount and Direction is model.Outflow or model.Inflow of the account
+	Amount    *Range `yaml:"amount" json:"amount"`
+	Direction string `yaml:"direction" json:"direction"`
+	// Institution, Account and Section are the institution code, the statement account number and the statement section
+	Institution string `yaml:"institution" json:"institution"`
+	Account     string `yaml:"account" json:"account"`
+	Section     string `yaml:"section" json:"section"`
+	// From and To are the inclusive "2006-01-02" dates of the posting date
+	From string `yaml:"from" json:"from"`
+	To   string `yaml:"to" json:"to"`
+}
+
+// File is the rules file
+type File struct {
+	Rules []Rule `yaml:"rules" json:"rules"`
+}
+
+// Result is the category of a transaction and the explanation of the rule that matched
+type Result struct {
+	Category    string
+	Rule        string
+	Explanation string
+}
+
+type compiledRule struct {
+	Rule
+	order       int
+	description *regexp.Regexp
+	from, to    time.Time
+}
+
+// Engine applies the rules
+type Engine struct {
+	rules []compiledRule
+}
+
+// Load loads the rules file, the ".json" files are read as json and the others as yaml
+func Load(filename string) (*Engine, error) {
+	var f File
+	if err := util.DecodeFile(filename, &f); err != nil {
+		return nil, err
+	}
+
+	e, err := New(f.Rules)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", filename, err)
+	}
+	return e, nil
+}
+
+// New validates the rules and returns the engine applying them in priority order
+func New(rules []Rule) (*Engine, error) {
+	e := &Engine{rules: make([]compiledRule, 0, len(rules))}
+	for i, r := range rules {
+		c := compiledRule{Rule: r, order: i}
+		if c.Name == "" {
+			c.Name = fmt.Sprintf("rule %d", i+1)
+		}
+		if err := ValidateCategory(r.Category); err != nil {
+			return nil, fmt.Errorf("%s: %v", c.Name, err)
+		}
+		if r.Direction != "" && r.Direction != model.Outflow && r.Direction != model.Inflow {
+			return nil, fmt.Errorf("%s: unknown direction %q", c.Name, r.Direction)
+		}
+
+		var err error
+		if r.Description != "" {
+			if c.description, err = regexp.Compile("(?i)" + r.Description); err != nil {
+				return nil, fmt.Errorf("%s: invalid description pattern: %v", c.Name, err)
+			}
+		}
+		if r.From != "" {
+			if c.from, err = time.Parse("2006-01-02", r.From); err != nil {
+				return nil, fmt.Errorf("%s: invalid from date: %v", c.Name, err)
+			}
+		}
+		if r.To != "" {
+			if c.to, err = time.Parse("2006-01-02", r.To); err != nil {
+				return nil, fmt.Errorf("%s: invalid to date: %v", c.Name, err)
+			}
+		}
+		e.rules = append(e.rules, c)
+	}
+
+	sort.SliceStable(e.rules, func(i, j int) bool {
+		return e.rules[i].Priority > e.rules[j].Priority
+	})
+	return e, nil
+}
+
+// Categorize returns the category of the first rule matching the transaction of the statement
+func (e *Engine) Categorize(s model.Statement, tx model.Transaction) (Result, bool) {
+	for _, r := range e.rules {
+		if reasons, ok := r.match(s, tx); ok {
+			return Result{
+				Category:    r.Category,
+				Rule:        r.Name,
+				Explanation: fmt.Sprintf("rule %q (priority %d): %s", r.Name, r.Priority, strings.Join(reasons, ", ")),
+			}, true
+		}
+	}
+	return Result{}, false
+}
+
+// Apply sets the spending category of the transactions without one, in place, and returns the results by transaction ID.
+// The transfers between the accounts are not spending and are left uncategorized.
+// The transactions without an ID get their stable ID assigned first.
+func (e *Engine) Apply(statements []model.Statement) map[string]Result {
+	results := map[string]Result{}
+	for i := range statements {
+		statements[i].AssignIDs()
+		for k := range statements[i].Transactions {
+			tx := &statements[i].Transactions[k]
+			if tx.SpendingCategory != "" || tx.TransferID != "" {
+				continue
+			}
+			if result, ok := e.Categorize(statements[i], *tx); ok {
+				tx.SpendingCategory = result.Category
+				results[tx.ID] = result
+			}
+		}
+	}
+	return results
+}
+
+// match returns the reasons the rule matches the transaction
+func (r compiledRule) match(s model.Statement, tx model.Transaction) ([]string, bool) {
+	var reasons []string
+
+	if r.description != nil {
+		if !r.description.MatchString(tx.Description) {
+			return nil, false
+		}
+		reasons = append(reasons, fmt.Sprintf("description matches %q", r.Rule.Description))
+	}
+
+	if r.Amount != nil {
+		amount := math.Abs(tx.Amount)
+		if (r.Amount.Min != 0 && amount < r.Amount.Min) || (r.Amount.Max != 0 && amount > r.Amount.Max) {
+			return nil, false
+		}
+		reasons = append(reasons, fmt.Sprintf("amount %.2f %s", amount, r.Amount))
+	}
+
+	if r.Direction != "" {
+		flow := s.Flow(tx)
+		if (r.Direction == model.Outflow && flow >= 0) || (r.Direction == model.Inflow && flow <= 0) {
+			return nil, false
+		}
+		reasons = append(reasons, "direction is "+r.Direction)
+	}
+
+	for _, condition := range []struct{ name, expected, actual string }{
+		{"institution", r.Institution, s.Institution},
+		{"account", r.Account, s.AccountNumber},
+		{"section", r.Section, tx.Category},
+	} {
+		if condition.expected == "" {
+			continue
+		}
+		if !strings.EqualFold(condition.expected, condition.actual) {
+			return nil, false
+		}
+		reasons = append(reasons, fmt.Sprintf("%s is %q", condition.name, condition.actual))
+	}
+
+	if !r.from.IsZero() || !r.to.IsZero() {
+		if (!r.from.IsZero() && tx.PostingDate.Before(r.from)) || (!r.to.IsZero() && tx.PostingDate.After(r.to)) {
+			return nil, false
+		}
+		posted := "posted " + tx.PostingDate.Format("2006-01-02")
+		switch {
+		case r.from.IsZero():
+			reasons = append(reasons, fmt.Sprintf("%s on or before %s", posted, r.To))
+		case r.to.IsZero():
+			reasons = append(reasons, fmt.Sprintf("%s on or after %s", posted, r.From))
+		default:
+			reasons = append(reasons, fmt.Sprintf("%s within [%s, %s]", posted, r.From, r.To))
+		}
+	}
+
+	if len(reasons) == 0 {
+		reasons = append(reasons, "no conditions")
+	}
+	return reasons, true
+}
+
+// ValidateCategory checks the category is not empty and has no empty level
+func ValidateCategory(category string) error {
+	if category == "" {
+		return fmt.Errorf("missing category")
+	}
+	for _, level := range strings.Split(category, ":") {
+		if strings.TrimSpace(level) == "" {
+			return fmt.Errorf("invalid category %q", category)
+		}
+	}
+	return nil
+}
+
+// Parent returns the parent of the category, e.g. "Home" for "Home:Improvement", empty for the top level categories
+func Parent(category string) string {
+	if i := strings.LastIndex(category, ":"); i >= 0 {
+		return category[:i]
+	}
+	return ""
+}
+
+// Top returns the top level of the category, e.g. "Home" for "Home:Improvement"
+func Top(category string) string {
+	top, _, _ := strings.Cut(category, ":")
+	return top
+}
@@ -0,0 +1,140 @@
+package categorize
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/muly/bank-tx/model"
+)
+
+func TestCategorize(t *testing.T) {
+	e, err := Load("rules.example.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	card := model.Statement{Institution: model.BofA, AccountType: model.CreditCard, AccountNumber: "4400 1234 5678 1234"}
+	checking := model.Statement{Institution: model.TD, AccountType: model.Checking, AccountNumber: "123-4567890"}
+	date := time.Date(2024, 9, 16, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		statement model.Statement
+		tx        model.Transaction
+		expected  string
+		rule      string
+	}{
+		{"groceries", card, model.Transaction{PostingDate: date, Description: "COSTCO WHSE #1111", Amount: 120.50}, "Food:Groceries", "costco"},
+		{"priority before file order", card, model.Transaction{PostingDate: date, Description: "HOME DEPOT CAFE", Amount: 12}, "Home:Improvement", "hardware stores"},
+		{"section and dates", card, model.Transaction{PostingDate: date, Description: "ERERE RERE COUNTY SCHOOL", Amount: 42.75, Category: "Purchases and Adjustments"}, "Education", "school"},
+		{"outside the dates", card, model.Transaction{PostingDate: date.AddDate(-1, 0, 0), Description: "ERERE RERE COUNTY SCHOOL", Amount: 42.75, Category: "Purchases and Adjustments"}, "", ""},
+		{"other institution", checking, model.Transaction{PostingDate: date, Description: "ERERE RERE COUNTY SCHOOL", Amount: -42.75, Category: "Purchases and Adjustments"}, "", ""},
+		{"below the amount", checking, model.Transaction{PostingDate: date, Description: "EEERERERERE MTG PAYMENTS", Amount: -500}, "", ""},
+		{"inflow", checking, model.Transaction{PostingDate: date, Description: "EEERERERERE MTG PAYMENTS REFUND", Amount: 6208.46}, "", ""},
+		{"lowest priority", card, model.Transaction{PostingDate: date, Description: "INTEREST CHARGED ON PURCHASES", Category: "Interest Charged"}, "Fees:Interest", "interest"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, ok := e.Categorize(tt.statement, tt.tx)
+			if ok != (tt.expected != "") || result.Category != tt.expected || result.Rule != tt.rule {
+				t.Errorf("expected %q by %q, got %+v", tt.expected, tt.rule, result)
+			}
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		file    string
+		content string
+		valid   bool
+	}{
+		{"rules.json", `{"rules": [{"name": "costco", "category": "Food:Groceries", "description": "COSTCO"}]}`, true},
+		{"missing-category.yaml", "rules:\n  - name: costco\n    description: COSTCO\n", false},
+		{"empty-level.yaml", "rules:\n  - category: Food::Groceries\n", false},
+		{"invalid-pattern.yaml", "rules:\n  - category: Food\n    description: \"(\"\n", false},
+		{"invalid-date.yaml", "rules:\n  - category: Food\n    from: 01/02/2024\n", false},
+		{"invalid-direction.yaml", "rules:\n  - category: Food\n    direction: out\n", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			path := filepath.Join(dir, tt.file)
+			if err := os.WriteFile(path, []byte(tt.content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			_, err := Load(path)
+			if (err == nil) != tt.valid {
+				t.Errorf("expected valid %v, got %v", tt.valid, err)
+			}
+		})
+	}
+}
+
+func TestHierarchy(t *testing.T) {
+	if got := Parent("Home:Improvement:Tools"); got != "Home:Improvement" {
+		t.Errorf("unexpected parent %q", got)
+	}
+	if got := Parent("Home"); got != "" {
+		t.Errorf("unexpected parent %q", got)
+	}
+	if got := Top("Home:Improvement:Tools"); got != "Home" {
+		t.Errorf("unexpected top %q", got)
+	}
+}
+
+func TestCategorize_dateExplanation(t *testing.T) {
+	date := time.Date(2024, 9, 16, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		from, to string
+		expected string
+	}{
+		{"from and to", "2024-01-01", "2024-12-31", `rule "school" (priority 0): posted 2024-09-16 within [2024-01-01, 2024-12-31]`},
+		{"from only", "2024-01-01", "", `rule "school" (priority 0): posted 2024-09-16 on or after 2024-01-01`},
+		{"to only", "", "2024-12-31", `rule "school" (priority 0): posted 2024-09-16 on or before 2024-12-31`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := New([]Rule{{Name: "school", Category: "Education", From: tt.from, To: tt.to}})
+			if err != nil {
+				t.Fatal(err)
+			}
+			result, ok := e.Categorize(model.Statement{}, model.Transaction{PostingDate: date, Amount: 42.75})
+			if !ok || result.Explanation != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, result.Explanation)
+			}
+		})
+	}
+}
+
+func TestApply(t *testing.T) {
+	e, err := Load("rules.example.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	date := time.Date(2024, 9, 16, 0, 0, 0, 0, time.UTC)
+	statements := []model.Statement{{Institution: model.TD, AccountType: model.Checking, AccountNumber: "123-4567890",
+		Transactions: []model.Transaction{
+			{PostingDate: date, Description: "EEERERERERE MTG PAYMENTS", Amount: -6208.46},
+			{PostingDate: date, Description: "EEERERERERE MTG PAYMENTS", Amount: -6208.46, TransferID: "e5c0d2a1b7f3946c8d21"},
+			{PostingDate: date, Description: "EEERERERERE MTG PAYMENTS", Amount: -6208.46, SpendingCategory: "Home:Rent"},
+		}}}
+
+	results := e.Apply(statements)
+	var categories []string
+	for _, tx := range statements[0].Transactions {
+		categories = append(categories, tx.SpendingCategory)
+	}
+	expected := []string{"Home:Mortgage", "", "Home:Rent"}
+	if len(results) != 1 || !reflect.DeepEqual(categories, expected) {
+		t.Errorf("expected the categories %q, got %q and %d results", expected, categories, len(results))
+	}
+}
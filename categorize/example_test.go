@@ -0,0 +1,39 @@
+package categorize
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/muly/bank-tx/model"
+	"github.com/muly/bank-tx/td"
+)
+
+func ExampleEngine_Apply() {
+	e, err := Load("rules.example.yaml")
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	data, err := os.ReadFile("../td/sample.txt")
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	s, err := td.ParseStatement(string(data))
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	statements := []model.Statement{s.ToModel()}
+	results := e.Apply(statements)
+	for _, tx := range statements[0].Transactions {
+		if result, ok := results[tx.ID]; ok {
+			fmt.Printf("%s %.2f %s: %s\n", tx.Description, tx.Amount, tx.SpendingCategory, result.Explanation)
+		}
+	}
+
+	// Output:
+	// ELECTRONIC PMT-WEB, EEERERERERE MTG PAYMENTS ****311244 -6208.46 Home:Mortgage: rule "mortgage" (priority 5): description matches "MTG PAYMENTS", amount 6208.46 at least 1000.00, direction is outflow
+}
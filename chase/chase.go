@@ -0,0 +1,182 @@
+// package chase provides the parsing functions to process the chase checking account statements
+// having the "TRANSACTION DETAIL" layout
+package chase
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/muly/bank-tx/model"
+	"github.com/muly/bank-tx/util"
+)
+
+// Transaction struct to hold transaction data
+type Transaction struct {
+	PostingDate time.Time
+	Description string
+	Amount      float64
+	Balance     float64
+}
+
+// Statement struct to hold overall statement info
+type Statement struct {
+	AccountNumber    string
+	PeriodStartDate  time.Time
+	PeriodEndDate    time.Time
+	BeginningBalance float64
+	EndingBalance    float64
+	Transactions     []Transaction
+}
+
+var (
+	rePeriod      = regexp.MustCompile(`^([A-Z][a-z]+ \d{1,2}, \d{4}) through ([A-Z][a-z]+ \d{1,2}, \d{4})$`)
+	reAccount     = regexp.MustCompile(`^Account Number:\s+(\d+)$`)
+	reBalance     = regexp.MustCompile(`^(Beginning|Ending) Balance (-?\$?[\d,]+\.\d{2})$`)
+	reSummary     = regexp.MustCompile(`^(Deposits and Additions|Checks Paid|ATM & Debit Card Withdrawals|Electronic Withdrawals|Other Withdrawals|Fees) (-?\$?[\d,]+\.\d{2})$`)
+	reTransaction = regexp.MustCompile(`^(\d{2}/\d{2})\s+(.+?)\s+(-?[\d,]+\.\d{2})\s+(-?[\d,]+\.\d{2})$`)
+)
+
+// ParseStatement parses the input data into a Statement struct
+func ParseStatement(data string) (*Statement, error) {
+	var statement Statement
+	var summaryTotal float64
+	var err error
+	inDetail := false
+
+	lines := strings.Split(data, "\n")
+
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+
+		if line == "" {
+			continue
+		}
+
+		// Parse statement period
+		if match := rePeriod.FindStringSubmatch(line); match != nil {
+			statement.PeriodStartDate, err = time.Parse("January 2, 2006", match[1])
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse start period: %v", err)
+			}
+			statement.PeriodEndDate, err = time.Parse("January 2, 2006", match[2])
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse end period: %v", err)
+			}
+			continue
+		}
+
+		// Parse account number
+		if match := reAccount.FindStringSubmatch(line); match != nil {
+			statement.AccountNumber = match[1]
+			continue
+		}
+
+		if line == "TRANSACTION DETAIL" {
+			inDetail = true
+			continue
+		}
+
+		if line == "DATE DESCRIPTION AMOUNT BALANCE" {
+			continue // Skip the transaction header line
+		}
+
+		// Parse beginning and ending balances, these are repeated in the summary and the transaction detail
+		if match := reBalance.FindStringSubmatch(line); match != nil {
+			balance, err := util.ParseFloat(match[2])
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse %s balance: %s, error: %v", strings.ToLower(match[1]), match[2], err)
+			}
+			if match[1] == "Beginning" {
+				statement.BeginningBalance = balance
+			} else {
+				statement.EndingBalance = balance
+			}
+			continue
+		}
+
+		// Parse the checking summary totals
+		if match := reSummary.FindStringSubmatch(line); match != nil && !inDetail {
+			amount, err := util.ParseFloat(match[2])
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse %s total: %s, error: %v", match[1], match[2], err)
+			}
+			summaryTotal += amount
+			continue
+		}
+
+		// Parse transaction lines
+		if match := reTransaction.FindStringSubmatch(line); match != nil && inDetail {
+			postingDate, err := util.AddYearToDate(match[1], "01/02", statement.PeriodStartDate, statement.PeriodEndDate)
+			if err != nil {
+				return nil, fmt.Errorf("error adding year to posting date: %v", err)
+			}
+			amount, err := util.ParseFloat(match[3])
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse amount: %s, error: %v", match[3], err)
+			}
+			balance, err := util.ParseFloat(match[4])
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse balance: %s, error: %v", match[4], err)
+			}
+
+			statement.Transactions = append(statement.Transactions, Transaction{
+				PostingDate: postingDate,
+				Description: match[2],
+				Amount:      amount,
+				Balance:     balance,
+			})
+			continue
+		}
+
+		log.Printf("unprocessed line: %v", line)
+	}
+
+	// Validate the summary totals
+	calculatedEndingBalance := util.RoundToTwoDecimal(statement.BeginningBalance + summaryTotal)
+	if calculatedEndingBalance != util.RoundToTwoDecimal(statement.EndingBalance) {
+		return nil, fmt.Errorf("summary balance mismatch: expected %.2f, got %.2f", statement.EndingBalance, calculatedEndingBalance)
+	}
+
+	// Validate every running balance against the cumulative sum of the transactions
+	balance := statement.BeginningBalance
+	for _, t := range statement.Transactions {
+		balance = util.RoundToTwoDecimal(balance + t.Amount)
+		if balance != util.RoundToTwoDecimal(t.Balance) {
+			return nil, fmt.Errorf("running balance mismatch on %s %q: expected %.2f, got %.2f",
+				t.PostingDate.Format("01/02/2006"), t.Description, t.Balance, balance)
+		}
+	}
+
+	if balance != util.RoundToTwoDecimal(statement.EndingBalance) {
+		return nil, fmt.Errorf("ending balance mismatch: expected %.2f, got %.2f", statement.EndingBalance, balance)
+	}
+
+	return &statement, nil
+}
+
+// ToModel converts the statement to the bank neutral model
+func (s Statement) ToModel() model.Statement {
+	transactions := make([]model.Transaction, 0, len(s.Transactions))
+	for _, tx := range s.Transactions {
+		transactions = append(transactions, model.Transaction{
+			TransactionDate: tx.PostingDate,
+			PostingDate:     tx.PostingDate,
+			Description:     tx.Description,
+			Amount:          tx.Amount,
+		})
+	}
+
+	return model.Statement{
+		Institution:      model.Chase,
+		AccountType:      model.Checking,
+		AccountNumber:    s.AccountNumber,
+		PeriodStartDate:  s.PeriodStartDate,
+		PeriodEndDate:    s.PeriodEndDate,
+		BeginningBalance: s.BeginningBalance,
+		EndingBalance:    s.EndingBalance,
+		Transactions:     transactions,
+	}
+}
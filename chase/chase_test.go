@@ -0,0 +1,67 @@
+package chase
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseStatement_runningBalance(t *testing.T) {
+	tests := []struct {
+		name    string
+		line    string
+		wantErr string
+	}{
+		{
+			name: "case 1: running balance matches",
+			line: "12/22 Payroll Ppd ID: 1111111111 1,600.00 6,886.43",
+		},
+		{
+			name:    "case 2: running balance mismatch",
+			line:    "12/22 Payroll Ppd ID: 1111111111 1,600.00 6,868.43",
+			wantErr: "running balance mismatch on 12/22/2023",
+		},
+		{
+			name:    "case 3: amount does not add up to the printed balance",
+			line:    "12/22 Payroll Ppd ID: 1111111111 1,060.00 6,886.43",
+			wantErr: "running balance mismatch on 12/22/2023",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := strings.Replace(sampleStatement, "12/22 Payroll Ppd ID: 1111111111 1,600.00 6,886.43", tt.line, 1)
+			_, err := ParseStatement(data)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("ParseStatement() unexpected error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ParseStatement() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+const sampleStatement = `
+JPMorgan Chase Bank, N.A.
+December 15, 2023 through January 12, 2024
+Account Number: 000000123456789
+
+CHECKING SUMMARY Chase Total Checking
+Beginning Balance $5,432.10
+Deposits and Additions 3,200.00
+ATM & Debit Card Withdrawals -145.67
+Electronic Withdrawals -1,950.00
+Ending Balance $6,536.43
+
+TRANSACTION DETAIL
+DATE DESCRIPTION AMOUNT BALANCE
+Beginning Balance $5,432.10
+12/18 Card Purchase 12/16 Costco Whse #1111 Town ST Card 1234 -145.67 5,286.43
+12/22 Payroll Ppd ID: 1111111111 1,600.00 6,886.43
+12/28 Zelle Payment To Some Name Jpm99a1b2c3d -100.00 6,786.43
+01/02 Online Payment 12345678 To Bank Of America 01/02 -1,850.00 4,936.43
+01/05 Payroll Ppd ID: 1111111111 1,600.00 6,536.43
+Ending Balance $6,536.43
+`
@@ -0,0 +1,46 @@
+package chase
+
+import (
+	"fmt"
+)
+
+func ExampleParseStatement() {
+	statement := `
+JPMorgan Chase Bank, N.A.
+December 15, 2023 through January 12, 2024
+Account Number: 000000123456789
+
+CHECKING SUMMARY Chase Total Checking
+Beginning Balance $5,432.10
+Deposits and Additions 3,200.00
+ATM & Debit Card Withdrawals -145.67
+Electronic Withdrawals -1,950.00
+Ending Balance $6,536.43
+
+TRANSACTION DETAIL
+DATE DESCRIPTION AMOUNT BALANCE
+Beginning Balance $5,432.10
+12/18 Card Purchase 12/16 Costco Whse #1111 Town ST Card 1234 -145.67 5,286.43
+12/22 Payroll Ppd ID: 1111111111 1,600.00 6,886.43
+12/28 Zelle Payment To Some Name Jpm99a1b2c3d -100.00 6,786.43
+01/02 Online Payment 12345678 To Bank Of America 01/02 -1,850.00 4,936.43
+01/05 Payroll Ppd ID: 1111111111 1,600.00 6,536.43
+Ending Balance $6,536.43
+	`
+
+	s, err := ParseStatement(statement)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	for _, tx := range s.Transactions {
+		fmt.Printf("%+v\n", tx)
+	}
+
+	// Output:
+	// {PostingDate:2023-12-18 00:00:00 +0000 UTC Description:Card Purchase 12/16 Costco Whse #1111 Town ST Card 1234 Amount:-145.67 Balance:5286.43}
+	// {PostingDate:2023-12-22 00:00:00 +0000 UTC Description:Payroll Ppd ID: 1111111111 Amount:1600 Balance:6886.43}
+	// {PostingDate:2023-12-28 00:00:00 +0000 UTC Description:Zelle Payment To Some Name Jpm99a1b2c3d Amount:-100 Balance:6786.43}
+	// {PostingDate:2024-01-02 00:00:00 +0000 UTC Description:Online Payment 12345678 To Bank Of America 01/02 Amount:-1850 Balance:4936.43}
+	// {PostingDate:2024-01-05 00:00:00 +0000 UTC Description:Payroll Ppd ID: 1111111111 Amount:1600 Balance:6536.43}
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/muly/bank-tx/continuity"
+	"github.com/muly/bank-tx/store"
+)
+
+// runCheck checks the continuity of the statement series of every account in the store
+func runCheck(args []string) error {
+	flags := flag.NewFlagSet("check", flag.ExitOnError)
+	db := flags.String("db", "bank-tx.db", "sqlite database file")
+	flags.Parse(args)
+
+	s, err := store.Open(*db)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+
+	statements, err := s.Statements()
+	if err != nil {
+		return err
+	}
+
+	issues := continuity.Check(statements)
+	for _, issue := range issues {
+		fmt.Println(issue)
+	}
+	if len(issues) > 0 {
+		return fmt.Errorf("%d continuity issues in %d statements", len(issues), len(statements))
+	}
+	fmt.Printf("%d statements, no continuity issues\n", len(statements))
+	return nil
+}
@@ -0,0 +1,144 @@
+package citi
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func loadSample(t *testing.T) string {
+	data, err := os.ReadFile("sample.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestParseStatement_sections(t *testing.T) {
+	purchases := []string{"Payments, Credits and Adjustments", "Standard Purchases", "Standard Purchases",
+		"Standard Purchases", "Standard Purchases"}
+
+	tests := []struct {
+		name           string
+		edits          [][2]string
+		wantCategories []string
+		wantErr        string
+	}{
+		{
+			name:           "case 1: payments and purchases",
+			wantCategories: purchases,
+		},
+		{
+			name: "case 2: fee and interest sections",
+			edits: [][2]string{
+				{"Fees +$0.00\nInterest +$0.00\nNew balance $257.32", "Fees +$40.00\nInterest +$1.25\nNew balance $298.57"},
+				{"Fees Charged\n", "Fees Charged\n01/14 LATE FEE - PAYMENT DUE 01/10 $40.00\n"},
+				{"Interest Charged\n", "Interest Charged\n01/14 INTEREST CHARGED TO STANDARD PURCH $1.25\n"},
+			},
+			wantCategories: append(append([]string(nil), purchases...), "Fees Charged", "Interest Charged"),
+		},
+		{
+			name:    "case 3: transactions do not add up to the new balance",
+			edits:   [][2]string{{"12/24 12/26 DUNKIN #111111 TOWN ST $3.64", "12/24 12/26 DUNKIN #111111 TOWN ST $4.36"}},
+			wantErr: "tx balance validation failed",
+		},
+		{
+			name:    "case 4: summary does not add up",
+			edits:   [][2]string{{"New balance $257.32", "New balance $275.32"}},
+			wantErr: "summary balance validation failed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := loadSample(t)
+			for _, edit := range tt.edits {
+				data = strings.Replace(data, edit[0], edit[1], 1)
+			}
+
+			s, err := ParseStatement(data)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("ParseStatement() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseStatement() unexpected error = %v", err)
+			}
+			if len(s.Transactions) != len(tt.wantCategories) {
+				t.Fatalf("ParseStatement() got %d transactions, want %d", len(s.Transactions), len(tt.wantCategories))
+			}
+			for i, tx := range s.Transactions {
+				if tx.Category != tt.wantCategories[i] {
+					t.Errorf("Transactions[%d] %q Category = %q, want %q", i, tx.Description, tx.Category, tt.wantCategories[i])
+				}
+			}
+		})
+	}
+}
+
+func TestParseTransaction(t *testing.T) {
+	start := time.Date(2023, 12, 15, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2024, 1, 14, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name            string
+		line            string
+		wantNil         bool
+		wantSale        string
+		wantPost        string
+		wantDescription string
+		wantAmount      float64
+	}{
+		{
+			name:            "case 1: sale and post dates across the year end",
+			line:            "12/31 01/02 DUNKIN #111111 TOWN ST $3.64",
+			wantSale:        "2023-12-31",
+			wantPost:        "2024-01-02",
+			wantDescription: "DUNKIN #111111 TOWN ST",
+			wantAmount:      3.64,
+		},
+		{
+			name:            "case 2: payment with the sale date only",
+			line:            "01/03 AUTOPAY 231228A1B2C3D AUTO-PMT -$842.17",
+			wantSale:        "2024-01-03",
+			wantPost:        "2024-01-03",
+			wantDescription: "AUTOPAY 231228A1B2C3D AUTO-PMT",
+			wantAmount:      -842.17,
+		},
+		{
+			name:    "case 3: not a transaction",
+			line:    "Sale Post Description Amount",
+			wantNil: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx, err := ParseTransaction(tt.line, start, end)
+			if err != nil {
+				t.Fatalf("ParseTransaction() unexpected error = %v", err)
+			}
+			if tt.wantNil {
+				if tx != nil {
+					t.Errorf("ParseTransaction() = %+v, want nil", tx)
+				}
+				return
+			}
+			if tx == nil {
+				t.Fatal("ParseTransaction() = nil")
+			}
+			if sale := tx.TransactionDate.Format("2006-01-02"); sale != tt.wantSale {
+				t.Errorf("TransactionDate = %s, want %s", sale, tt.wantSale)
+			}
+			if post := tx.PostingDate.Format("2006-01-02"); post != tt.wantPost {
+				t.Errorf("PostingDate = %s, want %s", post, tt.wantPost)
+			}
+			if tx.Description != tt.wantDescription || tx.Amount != tt.wantAmount {
+				t.Errorf("got %q %v, want %q %v", tx.Description, tx.Amount, tt.wantDescription, tt.wantAmount)
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package citi
+
+import (
+	"fmt"
+)
+
+func ExampleParseStatement() {
+	statement := `
+Citi Double Cash Card
+Account number ending in: 9012
+Billing Period: 12/15/23-01/14/24
+
+Account Summary
+Previous balance $842.17
+Payments -$842.17
+Credits -$0.00
+Purchases +$257.32
+Cash advances +$0.00
+Fees +$0.00
+Interest +$0.00
+New balance $257.32
+
+Account activity
+Sale Post Description Amount
+Payments, Credits and Adjustments
+01/03 AUTOPAY 231228A1B2C3D AUTO-PMT -$842.17
+Standard Purchases
+12/17 12/18 THE HOME DEPOT #3644 TOWN ST $64.70
+12/24 12/26 DUNKIN #111111 TOWN ST $3.64
+01/08 01/09 COSTCO WHSE #1206 TOWN ST $142.13
+01/12 01/13 HOMEDEPOT.COM 111-111-1111 BC $46.85
+Fees Charged
+TOTAL FEES CHARGED FOR THIS PERIOD $0.00
+Interest Charged
+TOTAL INTEREST CHARGED FOR THIS PERIOD $0.00
+	`
+
+	s, err := ParseStatement(statement)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	for _, tx := range s.Transactions {
+		fmt.Printf("%+v\n", tx)
+	}
+
+	// Output:
+	// {TransactionDate:2024-01-03 00:00:00 +0000 UTC PostingDate:2024-01-03 00:00:00 +0000 UTC Description:AUTOPAY 231228A1B2C3D AUTO-PMT Amount:-842.17 Category:Payments, Credits and Adjustments}
+	// {TransactionDate:2023-12-17 00:00:00 +0000 UTC PostingDate:2023-12-18 00:00:00 +0000 UTC Description:THE HOME DEPOT #3644 TOWN ST Amount:64.7 Category:Standard Purchases}
+	// {TransactionDate:2023-12-24 00:00:00 +0000 UTC PostingDate:2023-12-26 00:00:00 +0000 UTC Description:DUNKIN #111111 TOWN ST Amount:3.64 Category:Standard Purchases}
+	// {TransactionDate:2024-01-08 00:00:00 +0000 UTC PostingDate:2024-01-09 00:00:00 +0000 UTC Description:COSTCO WHSE #1206 TOWN ST Amount:142.13 Category:Standard Purchases}
+	// {TransactionDate:2024-01-12 00:00:00 +0000 UTC PostingDate:2024-01-13 00:00:00 +0000 UTC Description:HOMEDEPOT.COM 111-111-1111 BC Amount:46.85 Category:Standard Purchases}
+}
@@ -0,0 +1,250 @@
+// package classify provides the naive bayes classifier of the spending categories, trained on the transactions
+// already categorized, that suggests the category of the new transactions with a confidence score
+package classify
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/muly/bank-tx/categorize"
+	"github.com/muly/bank-tx/model"
+)
+
+// amountBuckets are the upper bounds of the amount features
+var amountBuckets = []float64{10, 25, 50, 100, 250, 500, 1000, 5000}
+
+// Example is a categorized transaction to learn from
+type Example struct {
+	AccountType string
+	Transaction model.Transaction
+}
+
+// Examples returns the transactions of the statements having a spending category set by the user, the transfers are left out.
+// The categories set by the rules and by the classifier itself would teach the classifier its own mistakes.
+func Examples(statements []model.Statement) []Example {
+	var examples []Example
+	for _, s := range statements {
+		for _, tx := range s.Transactions {
+			if tx.SpendingCategory != "" && tx.CategorySource == model.SourceManual && tx.TransferID == "" {
+				examples = append(examples, Example{AccountType: s.AccountType, Transaction: tx})
+			}
+		}
+	}
+	return examples
+}
+
+// Features returns the features of the transaction: the words of the description, without the numbers like
+// the store numbers, the amount bucket and the direction of the money
+func Features(accountType string, tx model.Transaction) []string {
+	var features []string
+	seen := map[string]bool{}
+	for _, word := range strings.FieldsFunc(strings.ToUpper(tx.Description), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if strings.IndexFunc(word, unicode.IsLetter) < 0 || seen[word] {
+			continue // numbers and repeated words
+		}
+		seen[word] = true
+		features = append(features, "word:"+word)
+	}
+
+	amount := math.Abs(tx.Amount)
+	bucket := fmt.Sprintf("amount:>%g", amountBuckets[len(amountBuckets)-1])
+	for _, limit := range amountBuckets {
+		if amount < limit {
+			bucket = fmt.Sprintf("amount:<%g", limit)
+			break
+		}
+	}
+	features = append(features, bucket)
+
+	if (model.Statement{AccountType: accountType}).Flow(tx) < 0 {
+		features = append(features, "direction:out")
+	} else {
+		features = append(features, "direction:in")
+	}
+	return features
+}
+
+// Model is the trained classifier, it is saved as json
+type Model struct {
+	// Examples is the number of the examples by category
+	Examples map[string]int `json:"examples"`
+	// Features is the count of every feature by category
+	Features map[string]map[string]int `json:"features"`
+	// Totals is the count of all the features by category
+	Totals     map[string]int  `json:"totals"`
+	Vocabulary map[string]bool `json:"vocabulary"`
+}
+
+// Suggestion is a category and the confidence, from 0 to 1, of it
+type Suggestion struct {
+	Category   string
+	Confidence float64
+	// Source is model.SourceModel for the classifier suggestions and model.SourceRules for the rule engine fallback
+	Source string
+}
+
+// Train trains the classifier on the examples
+func Train(examples []Example) *Model {
+	m := &Model{
+		Examples:   map[string]int{},
+		Features:   map[string]map[string]int{},
+		Totals:     map[string]int{},
+		Vocabulary: map[string]bool{},
+	}
+	for _, e := range examples {
+		category := e.Transaction.SpendingCategory
+		m.Examples[category]++
+		if m.Features[category] == nil {
+			m.Features[category] = map[string]int{}
+		}
+		for _, f := range Features(e.AccountType, e.Transaction) {
+			m.Features[category][f]++
+			m.Totals[category]++
+			m.Vocabulary[f] = true
+		}
+	}
+	return m
+}
+
+// Predict returns the categories of the transaction, the most likely first, with their confidence.
+// It uses the laplace smoothing for the features not seen with a category. It returns none when no word of
+// the description was seen in the training, as the amount and the direction alone would get a confidence they do not have.
+func (m *Model) Predict(accountType string, tx model.Transaction) []Suggestion {
+	examples := 0
+	for _, n := range m.Examples {
+		examples += n
+	}
+	if examples == 0 {
+		return nil
+	}
+
+	features := Features(accountType, tx)
+	known := false
+	for _, f := range features {
+		if strings.HasPrefix(f, "word:") && m.Vocabulary[f] {
+			known = true
+			break
+		}
+	}
+	if !known {
+		return nil
+	}
+	scores := map[string]float64{}
+	for category, n := range m.Examples {
+		score := math.Log(float64(n) / float64(examples))
+		denominator := float64(m.Totals[category] + len(m.Vocabulary))
+		for _, f := range features {
+			if !m.Vocabulary[f] {
+				continue // unknown features tell nothing
+			}
+			score += math.Log(float64(m.Features[category][f]+1) / denominator)
+		}
+		scores[category] = score
+	}
+
+	// softmax of the log probabilities
+	best := math.Inf(-1)
+	for _, score := range scores {
+		best = math.Max(best, score)
+	}
+	sum := 0.0
+	for _, score := range scores {
+		sum += math.Exp(score - best)
+	}
+
+	suggestions := make([]Suggestion, 0, len(scores))
+	for category, score := range scores {
+		suggestions = append(suggestions, Suggestion{Category: category, Confidence: math.Exp(score-best) / sum, Source: model.SourceModel})
+	}
+	sort.Slice(suggestions, func(i, j int) bool {
+		if suggestions[i].Confidence != suggestions[j].Confidence {
+			return suggestions[i].Confidence > suggestions[j].Confidence
+		}
+		return suggestions[i].Category < suggestions[j].Category
+	})
+	return suggestions
+}
+
+// Save saves the model as json
+func (m *Model) Save(filename string) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filename, data, 0o644)
+}
+
+// LoadModel loads the model saved by Save
+func LoadModel(filename string) (*Model, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	var m Model
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %v", filename, err)
+	}
+	return &m, nil
+}
+
+// CrossValidate returns the accuracy of the classifier on the examples by the k-fold cross validation:
+// every example is predicted by the model trained on the other folds. The folds are assigned round robin.
+func CrossValidate(examples []Example, k int) (float64, error) {
+	if k < 2 {
+		return 0, fmt.Errorf("cross validation needs at least 2 folds, got %d", k)
+	}
+	if len(examples) < k {
+		return 0, fmt.Errorf("cross validation with %d folds needs at least %d examples, got %d", k, k, len(examples))
+	}
+
+	correct := 0
+	for fold := 0; fold < k; fold++ {
+		var training, test []Example
+		for i, e := range examples {
+			if i%k == fold {
+				test = append(test, e)
+			} else {
+				training = append(training, e)
+			}
+		}
+
+		m := Train(training)
+		for _, e := range test {
+			suggestions := m.Predict(e.AccountType, e.Transaction)
+			if len(suggestions) > 0 && suggestions[0].Category == e.Transaction.SpendingCategory {
+				correct++
+			}
+		}
+	}
+	return float64(correct) / float64(len(examples)), nil
+}
+
+// Categorizer suggests the category by the model, falling back to the rule engine when the model is not confident enough
+type Categorizer struct {
+	Model *Model
+	Rules *categorize.Engine
+	// MinConfidence is the confidence the model suggestion needs to be used
+	MinConfidence float64
+}
+
+// Suggest returns the category of the transaction of the statement, false when neither the model nor the rules have one
+func (c Categorizer) Suggest(s model.Statement, tx model.Transaction) (Suggestion, bool) {
+	if c.Model != nil {
+		if suggestions := c.Model.Predict(s.AccountType, tx); len(suggestions) > 0 && suggestions[0].Confidence >= c.MinConfidence {
+			return suggestions[0], true
+		}
+	}
+	if c.Rules != nil {
+		if result, ok := c.Rules.Categorize(s, tx); ok {
+			return Suggestion{Category: result.Category, Confidence: 1, Source: model.SourceRules}, true
+		}
+	}
+	return Suggestion{}, false
+}
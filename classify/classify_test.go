@@ -0,0 +1,147 @@
+package classify
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/muly/bank-tx/categorize"
+	"github.com/muly/bank-tx/model"
+)
+
+func TestFeatures(t *testing.T) {
+	tests := []struct {
+		name        string
+		accountType string
+		tx          model.Transaction
+		expected    []string
+	}{
+		{"store number", model.CreditCard, model.Transaction{Description: "COSTCO WHSE #1111 COSTCO", Amount: 120.50},
+			[]string{"word:COSTCO", "word:WHSE", "amount:<250", "direction:out"}},
+		{"card payment", model.CreditCard, model.Transaction{Description: "PAYMENT - THANK YOU", Amount: -1500},
+			[]string{"word:PAYMENT", "word:THANK", "word:YOU", "amount:<5000", "direction:in"}},
+		{"checking deposit", model.Checking, model.Transaction{Description: "ACH DEPOSIT, ACME PAYROLL", Amount: 6377.30},
+			[]string{"word:ACH", "word:DEPOSIT", "word:ACME", "word:PAYROLL", "amount:>5000", "direction:in"}},
+		{"checking payment", model.Checking, model.Transaction{Description: "TST*WATERPARK - KIOSK 1", Amount: -8},
+			[]string{"word:TST", "word:WATERPARK", "word:KIOSK", "amount:<10", "direction:out"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := Features(tt.accountType, tt.tx)
+			if !reflect.DeepEqual(actual, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func examples() []Example {
+	var examples []Example
+	for _, tx := range []model.Transaction{
+		{Description: "COSTCO WHSE #1111", Amount: 120.50, SpendingCategory: "Food:Groceries"},
+		{Description: "COSTCO WHSE #1206", Amount: 88.10, SpendingCategory: "Food:Groceries"},
+		{Description: "SAFEWAY #0457", Amount: 75.99, SpendingCategory: "Food:Groceries"},
+		{Description: "SAFEWAY #1130", Amount: 61.30, SpendingCategory: "Food:Groceries"},
+		{Description: "SHELL OIL 5744", Amount: 41.07, SpendingCategory: "Auto:Fuel"},
+		{Description: "SHELL OIL 1290", Amount: 38.50, SpendingCategory: "Auto:Fuel"},
+		{Description: "CHEVRON 0093", Amount: 42.00, SpendingCategory: "Auto:Fuel"},
+		{Description: "CHEVRON 0211", Amount: 47.25, SpendingCategory: "Auto:Fuel"},
+	} {
+		examples = append(examples, Example{AccountType: model.CreditCard, Transaction: tx})
+	}
+	return examples
+}
+
+func TestCrossValidate(t *testing.T) {
+	accuracy, err := CrossValidate(examples(), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if accuracy != 1 {
+		t.Errorf("expected accuracy 1, got %v", accuracy)
+	}
+
+	if _, err := CrossValidate(examples(), 1); err == nil {
+		t.Error("expected an error for 1 fold")
+	}
+	if _, err := CrossValidate(examples()[:2], 3); err == nil {
+		t.Error("expected an error for fewer examples than folds")
+	}
+}
+
+func TestPredict_unknownWords(t *testing.T) {
+	m := Train(examples())
+	// the amount is a groceries one, but no word of the description was seen
+	if suggestions := m.Predict(model.CreditCard, model.Transaction{Description: "NETFLIX.COM", Amount: 75.99}); suggestions != nil {
+		t.Errorf("expected no suggestions, got %v", suggestions)
+	}
+	if suggestions := m.Predict(model.CreditCard, model.Transaction{Description: "SAFEWAY #2210", Amount: 75.99}); len(suggestions) == 0 || suggestions[0].Category != "Food:Groceries" {
+		t.Errorf("expected Food:Groceries first, got %v", suggestions)
+	}
+}
+
+func TestExamples(t *testing.T) {
+	statements := []model.Statement{{AccountType: model.CreditCard, Transactions: []model.Transaction{
+		{Description: "COSTCO WHSE #1111", Amount: 120.50, SpendingCategory: "Food:Groceries", CategorySource: model.SourceManual},
+		{Description: "PAYMENT - THANK YOU", Amount: -500, SpendingCategory: "Transfer", CategorySource: model.SourceManual, TransferID: "0123456789abcdef0123"},
+		{Description: "SHELL OIL 5744", Amount: 41.07},
+		{Description: "SAFEWAY #2210", Amount: 75.99, SpendingCategory: "Food:Groceries", CategorySource: model.SourceRules},
+		{Description: "NETFLIX.COM", Amount: 15.49, SpendingCategory: "Food:Groceries", CategorySource: model.SourceModel},
+	}}}
+	examples := Examples(statements)
+	if len(examples) != 1 || examples[0].Transaction.Description != "COSTCO WHSE #1111" {
+		t.Errorf("expected only the purchase categorized by the user, got %+v", examples)
+	}
+}
+
+func TestSaveLoad(t *testing.T) {
+	m := Train(examples())
+	filename := filepath.Join(t.TempDir(), "model.json")
+	if err := m.Save(filename); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadModel(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tx := model.Transaction{Description: "SHELL OIL 0001", Amount: 35}
+	expected, actual := m.Predict(model.CreditCard, tx), loaded.Predict(model.CreditCard, tx)
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	rules, err := categorize.Load("../categorize/rules.example.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := Categorizer{Model: Train(examples()), Rules: rules, MinConfidence: 0.8}
+	card := model.Statement{Institution: model.BofA, AccountType: model.CreditCard}
+
+	tests := []struct {
+		name     string
+		tx       model.Transaction
+		category string
+		source   string
+	}{
+		{"model", model.Transaction{Description: "COSTCO WHSE #0773", Amount: 99}, "Food:Groceries", "model"},
+		{"rules fallback", model.Transaction{Description: "THE HOME DEPOT #3644", Amount: 230.18}, "Home:Improvement", "rules"},
+		{"none", model.Transaction{Description: "UNKNOWN MERCHANT", Amount: 5}, "", ""},
+		{"unknown words", model.Transaction{Description: "NETFLIX.COM", Amount: 75.99}, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			suggestion, ok := c.Suggest(card, tt.tx)
+			if ok != (tt.category != "") {
+				t.Fatalf("expected found %v, got %v", tt.category != "", ok)
+			}
+			if suggestion.Category != tt.category || suggestion.Source != tt.source {
+				t.Errorf("expected %s by %s, got %s by %s", tt.category, tt.source, suggestion.Category, suggestion.Source)
+			}
+		})
+	}
+}
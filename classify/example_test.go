@@ -0,0 +1,28 @@
+package classify
+
+import (
+	"fmt"
+
+	"github.com/muly/bank-tx/model"
+)
+
+func ExampleModel_Predict() {
+	history := []Example{
+		{model.CreditCard, model.Transaction{Description: "COSTCO WHSE #1111 TOWN ST", Amount: 182.37, SpendingCategory: "Food:Groceries"}},
+		{model.CreditCard, model.Transaction{Description: "COSTCO WHSE #1206 TOWN ST", Amount: 95.12, SpendingCategory: "Food:Groceries"}},
+		{model.CreditCard, model.Transaction{Description: "SAFEWAY #0457 TOWN ST", Amount: 64.20, SpendingCategory: "Food:Groceries"}},
+		{model.CreditCard, model.Transaction{Description: "COSTCO GAS #1111 TOWN ST", Amount: 48.90, SpendingCategory: "Auto:Fuel"}},
+		{model.CreditCard, model.Transaction{Description: "SHELL OIL 5744 TOWN ST", Amount: 41.07, SpendingCategory: "Auto:Fuel"}},
+		{model.CreditCard, model.Transaction{Description: "THE HOME DEPOT #3644 TOWN ST", Amount: 230.18, SpendingCategory: "Home:Improvement"}},
+	}
+
+	m := Train(history)
+	for _, suggestion := range m.Predict(model.CreditCard, model.Transaction{Description: "COSTCO WHSE #0773 OTHER ST", Amount: 150.02}) {
+		fmt.Printf("%s %.2f\n", suggestion.Category, suggestion.Confidence)
+	}
+
+	// Output:
+	// Food:Groceries 0.86
+	// Auto:Fuel 0.08
+	// Home:Improvement 0.05
+}
@@ -0,0 +1,124 @@
+// package continuity provides the functions to check that the statements of each account form a complete series:
+// every statement begins with the ending balance of the previous one and the periods neither leave gaps nor overlap
+package continuity
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/muly/bank-tx/model"
+	"github.com/muly/bank-tx/util"
+)
+
+// Kinds of the issues
+const (
+	// BalanceBreak is a beginning balance different from the ending balance of the previous statement
+	BalanceBreak = "balance_break"
+	// Gap is a missing period between the end of a statement and the start of the next one
+	Gap = "gap"
+	// Overlap is a statement starting before the end of the previous one
+	Overlap = "overlap"
+)
+
+// Issue is a break in the statement series of an account, between the previous and the next statement
+type Issue struct {
+	Kind          string
+	Institution   string
+	AccountNumber string
+
+	PreviousStart time.Time
+	PreviousEnd   time.Time
+	NextStart     time.Time
+	NextEnd       time.Time
+
+	// PreviousEnding and NextBeginning are the balances of the balance breaks
+	PreviousEnding float64
+	NextBeginning  float64
+	// Days is the number of the days missing for the gaps and the days in both periods for the overlaps
+	Days int
+}
+
+func (i Issue) String() string {
+	account := i.Institution + " " + i.AccountNumber
+	switch i.Kind {
+	case BalanceBreak:
+		return fmt.Sprintf("%s: balance break between %s and %s: ending balance %.2f, next beginning balance %.2f (difference %.2f)",
+			account, i.PreviousEnd.Format("2006-01-02"), i.NextStart.Format("2006-01-02"),
+			i.PreviousEnding, i.NextBeginning, util.RoundToTwoDecimal(i.NextBeginning-i.PreviousEnding))
+	case Gap:
+		return fmt.Sprintf("%s: %d days missing from %s to %s", account, i.Days,
+			i.PreviousEnd.AddDate(0, 0, 1).Format("2006-01-02"), i.NextStart.AddDate(0, 0, -1).Format("2006-01-02"))
+	case Overlap:
+		return fmt.Sprintf("%s: %d days overlap between %s to %s and %s to %s", account, i.Days,
+			i.PreviousStart.Format("2006-01-02"), i.PreviousEnd.Format("2006-01-02"),
+			i.NextStart.Format("2006-01-02"), i.NextEnd.Format("2006-01-02"))
+	}
+	return fmt.Sprintf("%s: %s", account, i.Kind)
+}
+
+// Check checks the statement series of every account, the account is the institution and the account number.
+// The statements are ordered by their period, the input order does not matter.
+// The issues are ordered by account and period.
+func Check(statements []model.Statement) []Issue {
+	sorted := append([]model.Statement(nil), statements...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		if a.Institution != b.Institution {
+			return a.Institution < b.Institution
+		}
+		if a.AccountNumber != b.AccountNumber {
+			return a.AccountNumber < b.AccountNumber
+		}
+		if !a.PeriodStartDate.Equal(b.PeriodStartDate) {
+			return a.PeriodStartDate.Before(b.PeriodStartDate)
+		}
+		return a.PeriodEndDate.Before(b.PeriodEndDate)
+	})
+
+	var issues []Issue
+	for i := 1; i < len(sorted); i++ {
+		previous, next := sorted[i-1], sorted[i]
+		if previous.Institution != next.Institution || previous.AccountNumber != next.AccountNumber {
+			continue
+		}
+
+		issue := Issue{
+			Institution:    next.Institution,
+			AccountNumber:  next.AccountNumber,
+			PreviousStart:  previous.PeriodStartDate,
+			PreviousEnd:    previous.PeriodEndDate,
+			NextStart:      next.PeriodStartDate,
+			NextEnd:        next.PeriodEndDate,
+			PreviousEnding: previous.EndingBalance,
+			NextBeginning:  next.BeginningBalance,
+		}
+
+		// the next period starts the day after the previous one ends
+		switch missing := daysBetween(previous.PeriodEndDate, next.PeriodStartDate) - 1; {
+		case missing > 0:
+			issue.Kind, issue.Days = Gap, missing
+			issues = append(issues, issue)
+		case missing < 0:
+			end := previous.PeriodEndDate
+			if next.PeriodEndDate.Before(end) {
+				end = next.PeriodEndDate
+			}
+			issue.Kind, issue.Days = Overlap, daysBetween(next.PeriodStartDate, end)+1
+			issues = append(issues, issue)
+		}
+
+		if util.RoundToTwoDecimal(previous.EndingBalance) != util.RoundToTwoDecimal(next.BeginningBalance) {
+			issue.Kind, issue.Days = BalanceBreak, 0
+			issues = append(issues, issue)
+		}
+	}
+	return issues
+}
+
+// daysBetween returns the number of the calendar days from a to b
+func daysBetween(a, b time.Time) int {
+	a = time.Date(a.Year(), a.Month(), a.Day(), 0, 0, 0, 0, time.UTC)
+	b = time.Date(b.Year(), b.Month(), b.Day(), 0, 0, 0, 0, time.UTC)
+	return int(b.Sub(a).Hours() / 24)
+}
@@ -0,0 +1,36 @@
+package continuity
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/muly/bank-tx/model"
+)
+
+func ExampleCheck() {
+	date := func(month time.Month, day int) time.Time {
+		return time.Date(2023, month, day, 0, 0, 0, 0, time.UTC)
+	}
+	statement := func(start, end time.Time, beginning, ending float64) model.Statement {
+		return model.Statement{Institution: model.TD, AccountNumber: "123-4567890",
+			PeriodStartDate: start, PeriodEndDate: end, BeginningBalance: beginning, EndingBalance: ending}
+	}
+
+	issues := Check([]model.Statement{
+		statement(date(3, 21), date(4, 20), 10750.35, 10131.07),
+		statement(date(4, 21), date(5, 20), 10131.07, 9800.00),
+		// May 21 to June 20 is missing
+		statement(date(6, 21), date(7, 20), 9912.40, 9500.00),
+		// re-issued statement overlapping the previous one, with a corrected balance
+		statement(date(7, 15), date(8, 20), 9450.00, 9000.00),
+	})
+	for _, issue := range issues {
+		fmt.Println(issue)
+	}
+
+	// Output:
+	// td 123-4567890: 31 days missing from 2023-05-21 to 2023-06-20
+	// td 123-4567890: balance break between 2023-05-20 and 2023-06-21: ending balance 9800.00, next beginning balance 9912.40 (difference 112.40)
+	// td 123-4567890: 6 days overlap between 2023-06-21 to 2023-07-20 and 2023-07-15 to 2023-08-20
+	// td 123-4567890: balance break between 2023-07-20 and 2023-07-15: ending balance 9500.00, next beginning balance 9450.00 (difference -50.00)
+}
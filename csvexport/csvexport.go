@@ -0,0 +1,214 @@
+// package csvexport provides the functions to write the statements as csv, to any io.Writer or to one file per statement
+// named by a template, with the columns, the date format and the sign of the amounts configurable
+package csvexport
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/muly/bank-tx/model"
+	"github.com/muly/bank-tx/util"
+)
+
+// Columns
+const (
+	ID                     = "ID"
+	TransactionDate        = "TransactionDate"
+	PostingDate            = "PostingDate"
+	Description            = "Description"
+	ReferenceNumber        = "ReferenceNumber"
+	AccountNumber          = "AccountNumber"
+	Amount                 = "Amount"
+	Category               = "Category"
+	TxType                 = "TxType"
+	Counterparty           = "Counterparty"
+	Institution            = "Institution"
+	AccountType            = "AccountType"
+	StatementAccountNumber = "StatementAccountNumber"
+	StatementPeriod        = "StatementPeriod"
+)
+
+// Sign conventions of the amounts
+const (
+	// SignStatement keeps the model convention, the amounts are signed the way they move the statement balance
+	SignStatement = "statement"
+	// SignCashFlow has the money coming in as positive and going out as negative for every account type,
+	// i.e. the credit card purchases are negative
+	SignCashFlow = "cashflow"
+)
+
+// DefaultColumns are the columns written when the options have none
+var DefaultColumns = []string{TransactionDate, PostingDate, Description, ReferenceNumber, AccountNumber, Amount, Category, StatementAccountNumber, StatementPeriod}
+
+// DefaultNameTemplate names the files by the institution, the account and the statement period
+const DefaultNameTemplate = "{{.Institution}}_{{.AccountNumber}}_{{.Start}}_to_{{.End}}.csv"
+
+// Options of the csv output, the zero value writes the DefaultColumns with a header, "01/02/2006" dates and the statement sign
+type Options struct {
+	Columns    []string
+	DateLayout string
+	Sign       string
+	NoHeader   bool
+}
+
+func (o Options) withDefaults() (Options, error) {
+	if len(o.Columns) == 0 {
+		o.Columns = DefaultColumns
+	}
+	if o.DateLayout == "" {
+		o.DateLayout = "01/02/2006"
+	}
+	if o.Sign == "" {
+		o.Sign = SignStatement
+	}
+	if o.Sign != SignStatement && o.Sign != SignCashFlow {
+		return o, fmt.Errorf("unknown sign convention %q", o.Sign)
+	}
+	for _, column := range o.Columns {
+		if _, ok := columnValues[column]; !ok {
+			return o, fmt.Errorf("unknown column %q", column)
+		}
+	}
+	return o, nil
+}
+
+// columnValues returns the value of each column for a transaction of the statement
+var columnValues = map[string]func(s model.Statement, tx model.Transaction, o Options) string{
+	ID: func(s model.Statement, tx model.Transaction, o Options) string { return tx.ID },
+	TransactionDate: func(s model.Statement, tx model.Transaction, o Options) string {
+		return formatDate(tx.TransactionDate, o.DateLayout)
+	},
+	PostingDate: func(s model.Statement, tx model.Transaction, o Options) string {
+		return formatDate(tx.PostingDate, o.DateLayout)
+	},
+	Description:     func(s model.Statement, tx model.Transaction, o Options) string { return tx.Description },
+	ReferenceNumber: func(s model.Statement, tx model.Transaction, o Options) string { return tx.ReferenceNumber },
+	AccountNumber:   func(s model.Statement, tx model.Transaction, o Options) string { return tx.AccountNumber },
+	Amount: func(s model.Statement, tx model.Transaction, o Options) string {
+		amount := tx.Amount
+		if o.Sign == SignCashFlow {
+			amount = s.Flow(tx)
+		}
+		return fmt.Sprintf("%.2f", util.RoundToTwoDecimal(amount)+0)
+	},
+	Category:               func(s model.Statement, tx model.Transaction, o Options) string { return tx.Category },
+	TxType:                 func(s model.Statement, tx model.Transaction, o Options) string { return tx.TxType },
+	Counterparty:           func(s model.Statement, tx model.Transaction, o Options) string { return tx.Counterparty },
+	Institution:            func(s model.Statement, tx model.Transaction, o Options) string { return s.Institution },
+	AccountType:            func(s model.Statement, tx model.Transaction, o Options) string { return s.AccountType },
+	StatementAccountNumber: func(s model.Statement, tx model.Transaction, o Options) string { return s.AccountNumber },
+	StatementPeriod: func(s model.Statement, tx model.Transaction, o Options) string {
+		return fmt.Sprintf("%s-%s", s.PeriodStartDate.Format("2006-01-02"), s.PeriodEndDate.Format("2006-01-02"))
+	},
+}
+
+// Write writes the transactions of the statements as csv
+func Write(w io.Writer, statements []model.Statement, o Options) error {
+	o, err := o.withDefaults()
+	if err != nil {
+		return err
+	}
+
+	writer := csv.NewWriter(w)
+	if !o.NoHeader {
+		if err := writer.Write(o.Columns); err != nil {
+			return fmt.Errorf("failed to write header: %v", err)
+		}
+	}
+
+	record := make([]string, len(o.Columns))
+	for _, s := range statements {
+		for _, tx := range s.Transactions {
+			for i, column := range o.Columns {
+				record[i] = columnValues[column](s, tx, o)
+			}
+			if err := writer.Write(record); err != nil {
+				return fmt.Errorf("failed to write transaction %q: %v", tx.Description, err)
+			}
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("failed to write csv: %v", err)
+	}
+	return nil
+}
+
+// nameData holds the fields available to the file name template
+type nameData struct {
+	Institution   string
+	AccountType   string
+	AccountNumber string
+	Start         string
+	End           string
+}
+
+// WriteFiles writes every statement to its own file in dir, named by the text/template nameTemplate, e.g. DefaultNameTemplate.
+// The characters not safe in file names are replaced by "_". It returns the paths of the files written.
+func WriteFiles(dir, nameTemplate string, statements []model.Statement, o Options) ([]string, error) {
+	tmpl, err := template.New("name").Option("missingkey=error").Parse(nameTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid name template: %v", err)
+	}
+
+	paths := make([]string, 0, len(statements))
+	for _, s := range statements {
+		var name bytes.Buffer
+		err := tmpl.Execute(&name, nameData{
+			Institution:   s.Institution,
+			AccountType:   s.AccountType,
+			AccountNumber: s.AccountNumber,
+			Start:         s.PeriodStartDate.Format("2006-01-02"),
+			End:           s.PeriodEndDate.Format("2006-01-02"),
+		})
+		if err != nil {
+			return paths, fmt.Errorf("failed to name the file of statement %s: %v", s.AccountNumber, err)
+		}
+
+		path := filepath.Join(dir, safeName(name.String()))
+		if err := writeFile(path, s, o); err != nil {
+			return paths, err
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+func writeFile(path string, s model.Statement, o Options) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := Write(file, []model.Statement{s}, o); err != nil {
+		file.Close()
+		return fmt.Errorf("%s: %v", path, err)
+	}
+	return file.Close()
+}
+
+// safeName replaces the path separators, the characters reserved on windows and the spaces in the file name
+func safeName(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '/', '\\', '|', ':', '*', '?', '"', '<', '>', ' ', '\t', '\n':
+			return '_'
+		}
+		return r
+	}, name)
+}
+
+// formatDate formats the date, the zero date is left empty
+func formatDate(t time.Time, layout string) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(layout)
+}
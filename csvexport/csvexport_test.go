@@ -0,0 +1,73 @@
+package csvexport
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/muly/bank-tx/model"
+)
+
+var testStatement = model.Statement{
+	Institution:     model.TD,
+	AccountType:     model.Checking,
+	AccountNumber:   "123/4567890",
+	PeriodStartDate: time.Date(2023, 3, 21, 0, 0, 0, 0, time.UTC),
+	PeriodEndDate:   time.Date(2023, 4, 20, 0, 0, 0, 0, time.UTC),
+	Transactions: []model.Transaction{
+		{PostingDate: time.Date(2023, 4, 2, 0, 0, 0, 0, time.UTC), Description: "ACH DEPOSIT", Amount: 25},
+	},
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+func TestWrite_errors(t *testing.T) {
+	tests := []struct {
+		name string
+		o    Options
+	}{
+		{name: "writer error", o: Options{}},
+		{name: "unknown column", o: Options{Columns: []string{"Balance"}}},
+		{name: "unknown sign", o: Options{Sign: "inverted"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Write(failingWriter{}, []model.Statement{testStatement}, tt.o); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestWriteFiles(t *testing.T) {
+	dir := t.TempDir()
+
+	paths, err := WriteFiles(dir, DefaultNameTemplate, []model.Statement{testStatement}, Options{NoHeader: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := filepath.Join(dir, "td_123_4567890_2023-03-21_to_2023-04-20.csv")
+	if len(paths) != 1 || paths[0] != expected {
+		t.Fatalf("expected %s, got %v", expected, paths)
+	}
+
+	data, err := os.ReadFile(expected)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(data); got != ",04/02/2023,ACH DEPOSIT,,,25.00,,123/4567890,2023-03-21-2023-04-20\n" {
+		t.Errorf("unexpected csv %q", got)
+	}
+
+	if _, err := WriteFiles(dir, "{{.Bank}}.csv", []model.Statement{testStatement}, Options{}); err == nil {
+		t.Error("expected an error for the unknown template field")
+	}
+}
@@ -0,0 +1,64 @@
+package csvexport_test
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/muly/bank-tx/bofa_cc"
+	"github.com/muly/bank-tx/csvexport"
+	"github.com/muly/bank-tx/model"
+)
+
+const bofaStatement = `
+Account# 4400 1234 5678 1234
+September 12 - October 11, 2024
+Previous Balance $1,905.57
+Payments and Other Credits -$1,905.57
+Purchases and Adjustments $42.75
+Fees Charged $0.00
+Interest Charged $0.00
+New Balance Total $42.75
+Payments and Other Credits
+09/28 09/30 PAYMENT - THANK YOU 0027 1234 -1,905.57
+Purchases and Adjustments
+09/13 09/16 ERERE RERE COUNTY SCHOOL FDFDDF-DDFDFD DF 0881 1234 42.75
+`
+
+func ExampleWrite() {
+	s, err := bofa_cc.ParseStatement(bofaStatement)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	if err := csvexport.Write(os.Stdout, []model.Statement{s.ToModel()}, csvexport.Options{}); err != nil {
+		fmt.Println(err)
+	}
+
+	// Output:
+	// TransactionDate,PostingDate,Description,ReferenceNumber,AccountNumber,Amount,Category,StatementAccountNumber,StatementPeriod
+	// 09/28/2024,09/30/2024,PAYMENT - THANK YOU,0027,1234,-1905.57,Payments and Other Credits,4400 1234 5678 1234,2024-09-12-2024-10-11
+	// 09/13/2024,09/16/2024,ERERE RERE COUNTY SCHOOL FDFDDF-DDFDFD DF,0881,1234,42.75,Purchases and Adjustments,4400 1234 5678 1234,2024-09-12-2024-10-11
+}
+
+func ExampleWrite_options() {
+	s, err := bofa_cc.ParseStatement(bofaStatement)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	o := csvexport.Options{
+		Columns:    []string{csvexport.PostingDate, csvexport.Amount, csvexport.Description, csvexport.Institution},
+		DateLayout: "2006-01-02",
+		Sign:       csvexport.SignCashFlow,
+		NoHeader:   true,
+	}
+	if err := csvexport.Write(os.Stdout, []model.Statement{s.ToModel()}, o); err != nil {
+		fmt.Println(err)
+	}
+
+	// Output:
+	// 2024-09-30,1905.57,PAYMENT - THANK YOU,bofa
+	// 2024-09-16,-42.75,ERERE RERE COUNTY SCHOOL FDFDDF-DDFDFD DF,bofa
+}
@@ -0,0 +1,67 @@
+package csvimport
+
+import (
+	"math"
+
+	"github.com/muly/bank-tx/model"
+	"github.com/muly/bank-tx/util"
+)
+
+// MaxDateDrift is the number of days the posting date of the same transaction may differ between the csv and the statement
+const MaxDateDrift = 3
+
+// Report holds the result of a cross-check of the csv against a parsed statement
+type Report struct {
+	Matched int
+	// MissingInCSV are the statement transactions not found in the csv
+	MissingInCSV []model.Transaction
+	// MissingInStatement are the csv transactions of the statement period not found in the statement
+	MissingInStatement []model.Transaction
+}
+
+// OK reports whether the csv and the statement have the same transactions
+func (r Report) OK() bool {
+	return len(r.MissingInCSV) == 0 && len(r.MissingInStatement) == 0
+}
+
+// CrossCheck matches the transactions of the parsed statement with the csv transactions by amount and posting date.
+// Only the csv transactions within the statement period are considered.
+func CrossCheck(statement, imported model.Statement) Report {
+	var report Report
+
+	candidates := make([]model.Transaction, 0, len(imported.Transactions))
+	for _, tx := range imported.Transactions {
+		if tx.PostingDate.Before(statement.PeriodStartDate) || tx.PostingDate.After(statement.PeriodEndDate) {
+			continue
+		}
+		candidates = append(candidates, tx)
+	}
+	used := make([]bool, len(candidates))
+
+	for _, tx := range statement.Transactions {
+		best, bestDrift := -1, math.MaxFloat64
+		for i, c := range candidates {
+			if used[i] || util.RoundToTwoDecimal(c.Amount) != util.RoundToTwoDecimal(tx.Amount) {
+				continue
+			}
+			drift := math.Abs(c.PostingDate.Sub(tx.PostingDate).Hours() / 24)
+			if drift <= MaxDateDrift && drift < bestDrift {
+				best, bestDrift = i, drift
+			}
+		}
+		if best < 0 {
+			report.MissingInCSV = append(report.MissingInCSV, tx)
+			continue
+		}
+		used[best] = true
+		report.Matched++
+	}
+
+	for i, c := range candidates {
+		if !used[i] {
+			report.MissingInStatement = append(report.MissingInStatement, c)
+		}
+	}
+
+	return report
+}
@@ -0,0 +1,226 @@
+// package csvimport provides the functions to read the activity downloaded from the banks as csv into the bank neutral model,
+// so that the gaps can be filled when only the csv is available and the csv can be cross-checked against the parsed statements
+package csvimport
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/muly/bank-tx/model"
+	"github.com/muly/bank-tx/util"
+)
+
+// Mapping describes the columns of a bank's csv export. Columns are matched by their header name, empty names are not mapped.
+type Mapping struct {
+	Institution string
+	AccountType string
+
+	Date        string
+	PostingDate string
+	Description string
+	Reference   string
+	Category    string
+	Balance     string
+
+	// Amount is the signed amount column, Debit and Credit are used instead when the bank splits the amount in two columns
+	Amount string
+	Debit  string
+	Credit string
+
+	DateLayout string
+	// Negate flips the sign of Amount to the model convention, e.g. the banks exporting the card purchases as negative
+	Negate bool
+}
+
+// ReadStatement reads the csv rows into a statement. The transactions are ordered oldest first and the period spans
+// the first to the last transaction. The balances are only set when the mapping has a balance column.
+// The transactions get their stable IDs assigned, in the oldest first order.
+func ReadStatement(r io.Reader, m Mapping, accountNumber string) (*model.Statement, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read csv: %v", err)
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("empty csv")
+	}
+
+	columns, err := m.columns(records[0])
+	if err != nil {
+		return nil, err
+	}
+
+	type row struct {
+		tx      model.Transaction
+		balance float64
+	}
+	rows := make([]row, 0, len(records)-1)
+
+	for i, record := range records[1:] {
+		if len(record) == 1 && strings.TrimSpace(record[0]) == "" {
+			continue // blank line
+		}
+
+		value := func(name string) string {
+			index, ok := columns[name]
+			if !ok || index >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[index])
+		}
+
+		var tx model.Transaction
+		tx.TransactionDate, err = time.Parse(m.DateLayout, value(m.Date))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: failed to parse date: %v", i+2, err)
+		}
+		tx.PostingDate = tx.TransactionDate
+		if m.PostingDate != "" {
+			tx.PostingDate, err = time.Parse(m.DateLayout, value(m.PostingDate))
+			if err != nil {
+				return nil, fmt.Errorf("line %d: failed to parse posting date: %v", i+2, err)
+			}
+		}
+
+		tx.Amount, err = m.amount(value)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", i+2, err)
+		}
+
+		tx.Description = value(m.Description)
+		tx.ReferenceNumber = value(m.Reference)
+		tx.Category = value(m.Category)
+
+		var balance float64
+		if _, ok := columns[m.Balance]; ok {
+			balance, err = parseAmount(value(m.Balance))
+			if err != nil {
+				return nil, fmt.Errorf("line %d: failed to parse balance: %v", i+2, err)
+			}
+		}
+
+		rows = append(rows, row{tx: tx, balance: balance})
+	}
+
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("no transactions in csv")
+	}
+
+	// Most banks export the newest activity first
+	if rows[0].tx.PostingDate.After(rows[len(rows)-1].tx.PostingDate) {
+		for i, j := 0, len(rows)-1; i < j; i, j = i+1, j-1 {
+			rows[i], rows[j] = rows[j], rows[i]
+		}
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		return rows[i].tx.PostingDate.Before(rows[j].tx.PostingDate)
+	})
+
+	statement := model.Statement{
+		Institution:     m.Institution,
+		AccountType:     m.AccountType,
+		AccountNumber:   accountNumber,
+		PeriodStartDate: rows[0].tx.PostingDate,
+		PeriodEndDate:   rows[len(rows)-1].tx.PostingDate,
+		Transactions:    make([]model.Transaction, 0, len(rows)),
+	}
+	for _, r := range rows {
+		statement.Transactions = append(statement.Transactions, r.tx)
+	}
+
+	if _, ok := columns[m.Balance]; ok {
+		statement.BeginningBalance = util.RoundToTwoDecimal(rows[0].balance - rows[0].tx.Amount)
+		statement.EndingBalance = rows[len(rows)-1].balance
+	}
+
+	statement.AssignIDs()
+	return &statement, nil
+}
+
+// columns maps the column names of the mapping to their index in the header
+func (m Mapping) columns(header []string) (map[string]int, error) {
+	index := map[string]int{}
+	for i, name := range header {
+		// some exports start with a byte order mark
+		index[strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))] = i
+	}
+
+	if m.Date == "" || m.Description == "" || (m.Amount == "" && (m.Debit == "" || m.Credit == "")) {
+		return nil, fmt.Errorf("mapping needs the date, description and amount (or debit and credit) columns")
+	}
+
+	columns := map[string]int{}
+	for _, name := range []string{m.Date, m.PostingDate, m.Description, m.Amount, m.Debit, m.Credit} {
+		if name == "" {
+			continue
+		}
+		i, ok := index[name]
+		if !ok {
+			return nil, fmt.Errorf("missing column %q in csv header %v", name, header)
+		}
+		columns[name] = i
+	}
+
+	// the optional columns are not in every variant of the export
+	for _, name := range []string{m.Reference, m.Category, m.Balance} {
+		if i, ok := index[name]; ok && name != "" {
+			columns[name] = i
+		}
+	}
+
+	return columns, nil
+}
+
+// amount returns the amount of the row in the model sign convention
+func (m Mapping) amount(value func(string) string) (float64, error) {
+	if m.Amount != "" {
+		amount, err := parseAmount(value(m.Amount))
+		if err != nil {
+			return 0, fmt.Errorf("failed to parse amount: %v", err)
+		}
+		if m.Negate {
+			amount = -amount
+		}
+		return amount, nil
+	}
+
+	debit, err := parseAmount(value(m.Debit))
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse debit: %v", err)
+	}
+	credit, err := parseAmount(value(m.Credit))
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse credit: %v", err)
+	}
+
+	// debits increase the balance of a credit card and decrease the balance of a checking account
+	if m.AccountType == model.CreditCard {
+		return math.Abs(debit) - math.Abs(credit), nil
+	}
+	return math.Abs(credit) - math.Abs(debit), nil
+}
+
+// parseAmount parses the amounts like "$1,234.56", "-1234.56" and "(1,234.56)", an empty value is zero
+func parseAmount(s string) (float64, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, nil
+	}
+	negative := strings.HasPrefix(s, "(") && strings.HasSuffix(s, ")")
+	amount, err := util.ParseFloat(strings.Trim(s, "()"))
+	if err != nil {
+		return 0, err
+	}
+	if negative {
+		amount = -amount
+	}
+	return amount, nil
+}
@@ -0,0 +1,82 @@
+package csvimport_test
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/muly/bank-tx/csvimport"
+	"github.com/muly/bank-tx/td"
+)
+
+func ExampleReadStatement() {
+	data := `Date,Bank RTN,Account Number,Transaction Type,Description,Debit,Credit,Check Number,Balance
+2023-04-12,011103093,1234567890,DEBIT,"ELECTRONIC PMT-WEB, REREREER CK WEBXFR TRANSFER ****062167",1000.00,,,21885.67
+2023-04-08,011103093,1234567890,CREDIT,"ACH DEPOSIT, fererer  erereer dfdferr",,6377.30,,22885.67
+2023-04-02,011103093,1234567890,CREDIT,"ACH DEPOSIT, rere erer ererereL",,6377.30,,16508.37
+`
+
+	s, err := csvimport.ReadStatement(strings.NewReader(data), csvimport.TD, "123-4567890")
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Printf("%s %s %.2f %.2f\n", s.PeriodStartDate.Format("2006-01-02"), s.PeriodEndDate.Format("2006-01-02"), s.BeginningBalance, s.EndingBalance)
+	for _, tx := range s.Transactions {
+		fmt.Printf("%s | %s | %.2f | %s\n", tx.PostingDate.Format("2006-01-02"), tx.Description, tx.Amount, tx.Category)
+	}
+
+	// Output:
+	// 2023-04-02 2023-04-12 10131.07 21885.67
+	// 2023-04-02 | ACH DEPOSIT, rere erer ererereL | 6377.30 | CREDIT
+	// 2023-04-08 | ACH DEPOSIT, fererer  erereer dfdferr | 6377.30 | CREDIT
+	// 2023-04-12 | ELECTRONIC PMT-WEB, REREREER CK WEBXFR TRANSFER ****062167 | -1000.00 | DEBIT
+}
+
+func ExampleCrossCheck() {
+	statement, err := td.ParseStatement(`
+Statement Period: Mar 21 2023-Apr 20 2023
+Account # 123-4567890
+Beginning Balance 1,000.00
+Ending Balance 7,377.30
+Electronic Deposits
+03/22 TD ZELLE RECEIVED, erer434ree r34rere re5rerer4344re 418.00
+04/02 ACH DEPOSIT, rere erer ererereL 6,377.30
+Subtotal: 6,795.30
+Electronic Payments
+04/11 TD BILL PAY SERV, BANK OF AMERICA ONLINE PMT TDB****34454454 418.00
+Subtotal: 418.00
+`)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	data := `Posting Date,Description,Amount
+04/03/2023,"ACH DEPOSIT, rere erer ererereL",6377.30
+04/11/2023,"TD BILL PAY SERV, BANK OF AMERICA ONLINE PMT",-418.00
+04/21/2023,"ACH DEPOSIT, rere rer4tr rtrtrrtr",3745.84
+`
+	imported, err := csvimport.ReadStatement(strings.NewReader(data), csvimport.Mapping{
+		Date:        "Posting Date",
+		Description: "Description",
+		Amount:      "Amount",
+		DateLayout:  "01/02/2006",
+	}, "123-4567890")
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	report := csvimport.CrossCheck(statement.ToModel(), *imported)
+	fmt.Println("matched:", report.Matched)
+	for _, tx := range report.MissingInCSV {
+		fmt.Println("missing in csv:", tx.PostingDate.Format("2006-01-02"), tx.Description, tx.Amount)
+	}
+	for _, tx := range report.MissingInStatement {
+		fmt.Println("missing in statement:", tx.PostingDate.Format("2006-01-02"), tx.Description, tx.Amount)
+	}
+
+	// Output:
+	// matched: 2
+	// missing in csv: 2023-03-22 TD ZELLE RECEIVED, erer434ree r34rere re5rerer4344re 418
+}
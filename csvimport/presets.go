@@ -0,0 +1,69 @@
+package csvimport
+
+import (
+	"github.com/muly/bank-tx/model"
+)
+
+// Column mapping presets of the csv downloads
+var (
+	// TD is the td bank checking "Download account activity" export
+	TD = Mapping{
+		Institution: model.TD,
+		AccountType: model.Checking,
+		Date:        "Date",
+		Description: "Description",
+		Reference:   "Check Number",
+		Category:    "Transaction Type",
+		Balance:     "Balance",
+		Debit:       "Debit",
+		Credit:      "Credit",
+		DateLayout:  "2006-01-02",
+	}
+
+	// BofA is the bank of america credit card export, it has the purchases as negative
+	BofA = Mapping{
+		Institution: model.BofA,
+		AccountType: model.CreditCard,
+		Date:        "Posted Date",
+		Description: "Payee",
+		Reference:   "Reference Number",
+		Amount:      "Amount",
+		DateLayout:  "01/02/2006",
+		Negate:      true,
+	}
+
+	// Chase is the chase checking export
+	Chase = Mapping{
+		Institution: model.Chase,
+		AccountType: model.Checking,
+		Date:        "Posting Date",
+		Description: "Description",
+		Reference:   "Check or Slip #",
+		Category:    "Type",
+		Balance:     "Balance",
+		Amount:      "Amount",
+		DateLayout:  "01/02/2006",
+	}
+
+	// Amex is the american express card export, it has the charges as positive
+	Amex = Mapping{
+		Institution: model.Amex,
+		AccountType: model.CreditCard,
+		Date:        "Date",
+		Description: "Description",
+		Reference:   "Reference",
+		Category:    "Category",
+		Amount:      "Amount",
+		DateLayout:  "01/02/2006",
+	}
+)
+
+// Presets returns the mapping presets by their institution
+func Presets() map[string]Mapping {
+	return map[string]Mapping{
+		model.TD:    TD,
+		model.BofA:  BofA,
+		model.Chase: Chase,
+		model.Amex:  Amex,
+	}
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/muly/bank-tx/dedup"
+	"github.com/muly/bank-tx/store"
+)
+
+// runDedup reports the transactions found in more than one statement of the same account in the store, like a statement
+// and its re-issue, and removes them from the later statements with -apply
+func runDedup(args []string) error {
+	flags := flag.NewFlagSet("dedup", flag.ExitOnError)
+	db := flags.String("db", "bank-tx.db", "sqlite database file")
+	days := flags.Int("days", dedup.DefaultPolicy().MaxDateDrift, "number of the days the posting dates of the fuzzy duplicates may differ")
+	similarity := flags.Float64("similarity", dedup.DefaultPolicy().MinSimilarity, "minimal similarity, from 0 to 1, of the descriptions of the fuzzy duplicates")
+	exact := flags.Bool("exact", false, "report the exact duplicates only")
+	apply := flags.Bool("apply", false, "remove the duplicates from the store")
+	transfers := flags.String("transfers", "", "yaml or json transfer rules file matched again with -apply")
+	flags.Parse(args)
+
+	c, err := loadTransfers(*transfers)
+	if err != nil {
+		return err
+	}
+
+	s, err := store.Open(*db)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+
+	statements, err := s.Statements()
+	if err != nil {
+		return err
+	}
+
+	// the statements are ordered by account and period, so the earliest statement keeps the transaction
+	merged, report := dedup.Merge(statements, dedup.Policy{MaxDateDrift: *days, MinSimilarity: *similarity, ExactOnly: *exact})
+	if err := report.Write(os.Stdout); err != nil {
+		return err
+	}
+	fmt.Printf("%d duplicates in %d statements\n", len(report.Duplicates), len(statements))
+	if !*apply || len(report.Duplicates) == 0 {
+		return nil
+	}
+
+	changed := map[int]bool{}
+	for _, d := range report.Duplicates {
+		changed[d.Duplicate.Statement] = true
+	}
+	for i := range merged {
+		if !changed[i] {
+			continue
+		}
+		if _, err := s.ImportStatement(merged[i]); err != nil {
+			return err
+		}
+	}
+
+	// a removed transaction may have been the side of a transfer
+	pairs, err := s.MarkTransfers(c)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%d duplicates removed, %d transfers between the accounts\n", len(report.Duplicates), len(pairs))
+	return nil
+}
@@ -0,0 +1,270 @@
+// package dedup provides the functions to find the transactions that appear in more than one source of the same account,
+// like a statement and the csv download of the same month or a statement and its re-issue, and to merge them away
+package dedup
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"text/tabwriter"
+	"time"
+	"unicode"
+
+	"github.com/muly/bank-tx/model"
+	"github.com/muly/bank-tx/util"
+)
+
+// Kinds of the duplicates
+const (
+	// Exact duplicates have the same id, or the same posting date, amount and normalized description
+	Exact = "exact"
+	// Fuzzy duplicates have the same amount, posting dates within the drift and similar descriptions
+	Fuzzy = "fuzzy"
+)
+
+// Policy configures when two transactions are duplicates
+type Policy struct {
+	// MaxDateDrift is the number of days the posting dates of the fuzzy duplicates may differ
+	MaxDateDrift int
+	// MinSimilarity is the minimal similarity, from 0 to 1, of the descriptions of the fuzzy duplicates
+	MinSimilarity float64
+	// ExactOnly disables the fuzzy matching
+	ExactOnly bool
+}
+
+// DefaultPolicy returns the policy used by the zero Policy fields
+func DefaultPolicy() Policy {
+	return Policy{MaxDateDrift: 3, MinSimilarity: 0.5}
+}
+
+func (p Policy) withDefaults() Policy {
+	d := DefaultPolicy()
+	if p.MaxDateDrift == 0 {
+		p.MaxDateDrift = d.MaxDateDrift
+	}
+	if p.MinSimilarity == 0 {
+		p.MinSimilarity = d.MinSimilarity
+	}
+	return p
+}
+
+// Ref locates a transaction, Statement and Index are the indexes in the statements given and in their transactions
+type Ref struct {
+	Statement   int
+	Index       int
+	Transaction model.Transaction
+}
+
+// Duplicate is a transaction found again in a later source
+type Duplicate struct {
+	Kind      string
+	Kept      Ref
+	Duplicate Ref
+	// DateDrift is the number of days between the posting dates
+	DateDrift int
+	// Similarity of the descriptions, 1 for the exact duplicates
+	Similarity float64
+}
+
+// Report holds the duplicates found
+type Report struct {
+	Duplicates []Duplicate
+}
+
+// Find finds the duplicates among the statements of the same account, the account is the institution and the account number.
+// The statements earlier in the slice take precedence, so pass the parsed statements before the csv downloads.
+// The transactions of the same statement are never duplicates of each other, as the charges the bank really posted twice
+// are both on the statement, and a transaction is the duplicate of at most one transaction of each earlier statement.
+// Only the statements whose periods overlap are compared, and only their transactions posted within the overlap, so the
+// same charge of the adjacent months is not a duplicate.
+func Find(statements []model.Statement, p Policy) Report {
+	p = p.withDefaults()
+	var report Report
+	dropped := map[[2]int]bool{}
+
+	for j := range statements {
+		var kept []Ref
+		var within []overlap
+		for i := 0; i < j; i++ {
+			if statements[i].Institution != statements[j].Institution || statements[i].AccountNumber != statements[j].AccountNumber {
+				continue
+			}
+			o, ok := overlapOf(statements[i], statements[j])
+			if !ok {
+				continue
+			}
+			for k, tx := range statements[i].Transactions {
+				if !dropped[[2]int{i, k}] && o.contains(tx.PostingDate) {
+					kept = append(kept, Ref{Statement: i, Index: k, Transaction: tx})
+					within = append(within, o)
+				}
+			}
+		}
+
+		used := make([]bool, len(kept))
+		for k, tx := range statements[j].Transactions {
+			best := -1
+			var bestMatch Duplicate
+			for c, candidate := range kept {
+				if used[c] || !within[c].contains(tx.PostingDate) {
+					continue
+				}
+				match, ok := p.match(candidate.Transaction, tx)
+				if ok && (best < 0 || better(match, bestMatch)) {
+					best, bestMatch = c, match
+				}
+			}
+			if best < 0 {
+				continue
+			}
+			used[best] = true
+			bestMatch.Kept = kept[best]
+			bestMatch.Duplicate = Ref{Statement: j, Index: k, Transaction: tx}
+			report.Duplicates = append(report.Duplicates, bestMatch)
+			dropped[[2]int{j, k}] = true
+		}
+	}
+
+	return report
+}
+
+// overlap is the part of the periods two statements have in common
+type overlap struct {
+	start, end time.Time
+}
+
+func (o overlap) contains(t time.Time) bool {
+	return !t.Before(o.start) && !t.After(o.end)
+}
+
+// overlapOf returns the overlap of the periods of the statements and false when they do not overlap
+func overlapOf(a, b model.Statement) (overlap, bool) {
+	startA, endA := period(a)
+	startB, endB := period(b)
+	o := overlap{start: startA, end: endA}
+	if startB.After(o.start) {
+		o.start = startB
+	}
+	if endB.Before(o.end) {
+		o.end = endB
+	}
+	return o, !o.start.After(o.end)
+}
+
+// period returns the period of the statement, the first and the last posting dates for the sources without one
+func period(s model.Statement) (time.Time, time.Time) {
+	if !s.PeriodStartDate.IsZero() || !s.PeriodEndDate.IsZero() {
+		return s.PeriodStartDate, s.PeriodEndDate
+	}
+	var start, end time.Time
+	for i, tx := range s.Transactions {
+		if i == 0 || tx.PostingDate.Before(start) {
+			start = tx.PostingDate
+		}
+		if i == 0 || tx.PostingDate.After(end) {
+			end = tx.PostingDate
+		}
+	}
+	return start, end
+}
+
+// Merge returns the statements without the duplicates of the earlier statements, along with the report of the duplicates removed
+func Merge(statements []model.Statement, p Policy) ([]model.Statement, Report) {
+	report := Find(statements, p)
+	dropped := map[[2]int]bool{}
+	for _, d := range report.Duplicates {
+		dropped[[2]int{d.Duplicate.Statement, d.Duplicate.Index}] = true
+	}
+
+	merged := make([]model.Statement, len(statements))
+	for i, s := range statements {
+		merged[i] = s
+		merged[i].Transactions = make([]model.Transaction, 0, len(s.Transactions))
+		for k, tx := range s.Transactions {
+			if !dropped[[2]int{i, k}] {
+				merged[i].Transactions = append(merged[i].Transactions, tx)
+			}
+		}
+	}
+	return merged, report
+}
+
+// Write writes the report as a table
+func (r Report) Write(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "KIND\tKEPT\tDUPLICATE\tAMOUNT\tDRIFT\tSIMILARITY")
+	for _, d := range r.Duplicates {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%.2f\t%d\t%.2f\n", d.Kind, describe(d.Kept), describe(d.Duplicate),
+			d.Duplicate.Transaction.Amount, d.DateDrift, d.Similarity)
+	}
+	return tw.Flush()
+}
+
+func describe(r Ref) string {
+	return fmt.Sprintf("#%d %s %s", r.Statement, r.Transaction.PostingDate.Format("2006-01-02"), r.Transaction.Description)
+}
+
+// match tells if the later transaction b is a duplicate of a
+func (p Policy) match(a, b model.Transaction) (Duplicate, bool) {
+	if util.RoundToTwoDecimal(a.Amount) != util.RoundToTwoDecimal(b.Amount) {
+		return Duplicate{}, false
+	}
+
+	drift := int(math.Round(math.Abs(b.PostingDate.Sub(a.PostingDate).Hours() / 24)))
+	if (a.ID != "" && a.ID == b.ID) ||
+		(drift == 0 && model.NormalizeDescription(a.Description) == model.NormalizeDescription(b.Description)) {
+		return Duplicate{Kind: Exact, DateDrift: drift, Similarity: 1}, true
+	}
+
+	if p.ExactOnly || drift > p.MaxDateDrift {
+		return Duplicate{}, false
+	}
+	similarity := Similarity(a.Description, b.Description)
+	if similarity < p.MinSimilarity {
+		return Duplicate{}, false
+	}
+	return Duplicate{Kind: Fuzzy, DateDrift: drift, Similarity: similarity}, true
+}
+
+// better tells if the match a is better than b: exact before fuzzy, then the most similar, then the closest dates
+func better(a, b Duplicate) bool {
+	if a.Kind != b.Kind {
+		return a.Kind == Exact
+	}
+	if a.Similarity != b.Similarity {
+		return a.Similarity > b.Similarity
+	}
+	return a.DateDrift < b.DateDrift
+}
+
+// Similarity returns the overlap, from 0 to 1, of the words of the descriptions: the number of the common words over the
+// number of the words of the shorter description, as the csv downloads often add the location to the description.
+// The words are the runs of letters and digits, so the punctuation and the layout of the source do not matter.
+func Similarity(a, b string) float64 {
+	wordsA, wordsB := words(a), words(b)
+	if len(wordsA) == 0 || len(wordsB) == 0 {
+		if len(wordsA) == len(wordsB) {
+			return 1
+		}
+		return 0
+	}
+
+	common := 0
+	for w := range wordsA {
+		if wordsB[w] {
+			common++
+		}
+	}
+	return float64(common) / math.Min(float64(len(wordsA)), float64(len(wordsB)))
+}
+
+func words(s string) map[string]bool {
+	set := map[string]bool{}
+	for _, w := range strings.FieldsFunc(strings.ToUpper(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		set[w] = true
+	}
+	return set
+}
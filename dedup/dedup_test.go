@@ -0,0 +1,132 @@
+package dedup
+
+import (
+	"testing"
+
+	"github.com/muly/bank-tx/model"
+)
+
+func TestFind(t *testing.T) {
+	charge := model.Transaction{PostingDate: date(5), Description: "NETFLIX.COM", Amount: 15.49}
+	other := model.Transaction{PostingDate: date(6), Description: "SHELL OIL 5744", Amount: 15.49}
+	reissued := model.Transaction{PostingDate: date(5), Description: "NETFLIX.COM LOS GATOS CA", Amount: 15.49}
+
+	tests := []struct {
+		name       string
+		statements []model.Statement
+		policy     Policy
+		expected   []string
+	}{
+		{
+			name:       "same statement charges are kept",
+			statements: []model.Statement{{Transactions: []model.Transaction{charge, charge}}},
+			expected:   nil,
+		},
+		{
+			name: "charge posted twice keeps the second one",
+			statements: []model.Statement{
+				{Transactions: []model.Transaction{charge, charge}},
+				{Transactions: []model.Transaction{charge}},
+			},
+			expected: []string{Exact},
+		},
+		{
+			name: "fuzzy duplicate",
+			statements: []model.Statement{
+				{Transactions: []model.Transaction{other, charge}},
+				{Transactions: []model.Transaction{reissued}},
+			},
+			expected: []string{Fuzzy},
+		},
+		{
+			name: "exact only policy",
+			statements: []model.Statement{
+				{Transactions: []model.Transaction{charge}},
+				{Transactions: []model.Transaction{reissued}},
+			},
+			policy:   Policy{ExactOnly: true},
+			expected: nil,
+		},
+		{
+			name: "other account",
+			statements: []model.Statement{
+				{AccountNumber: "1234", Transactions: []model.Transaction{charge}},
+				{AccountNumber: "5678", Transactions: []model.Transaction{charge}},
+			},
+			expected: nil,
+		},
+		{
+			name: "same charge of the adjacent months",
+			statements: []model.Statement{
+				{PeriodStartDate: date(1), PeriodEndDate: date(15), Transactions: []model.Transaction{
+					{PostingDate: date(14), Description: "DUNKIN #343475", Amount: 3.64},
+				}},
+				{PeriodStartDate: date(16), PeriodEndDate: date(30), Transactions: []model.Transaction{
+					{PostingDate: date(16), Description: "DUNKIN #343475", Amount: 3.64},
+				}},
+			},
+			expected: nil,
+		},
+		{
+			name: "csv download overlapping the statement",
+			statements: []model.Statement{
+				{PeriodStartDate: date(1), PeriodEndDate: date(15), Transactions: []model.Transaction{
+					{PostingDate: date(14), Description: "DUNKIN #343475", Amount: 3.64},
+				}},
+				{Transactions: []model.Transaction{
+					{PostingDate: date(14), Description: "DUNKIN #343475 BOSTON MA", Amount: 3.64},
+					{PostingDate: date(16), Description: "DUNKIN #343475 BOSTON MA", Amount: 3.64},
+				}},
+			},
+			expected: []string{Fuzzy},
+		},
+		{
+			name: "third source matches the first",
+			statements: []model.Statement{
+				{Transactions: []model.Transaction{charge}},
+				{Transactions: []model.Transaction{charge}},
+				{Transactions: []model.Transaction{reissued}},
+			},
+			expected: []string{Exact, Fuzzy},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := Find(tt.statements, tt.policy)
+			if len(report.Duplicates) != len(tt.expected) {
+				t.Fatalf("expected %d duplicates, got %+v", len(tt.expected), report.Duplicates)
+			}
+			for i, d := range report.Duplicates {
+				if d.Kind != tt.expected[i] {
+					t.Errorf("duplicate %d: expected %s, got %s", i, tt.expected[i], d.Kind)
+				}
+				if d.Kept.Statement >= d.Duplicate.Statement {
+					t.Errorf("duplicate %d: kept the later statement %+v", i, d)
+				}
+			}
+		})
+	}
+}
+
+func TestMerge_adjacentMonths(t *testing.T) {
+	statements := []model.Statement{
+		{PeriodStartDate: date(1), PeriodEndDate: date(15), Transactions: []model.Transaction{
+			{PostingDate: date(14), Description: "DUNKIN #343475", Amount: 3.64},
+		}},
+		{PeriodStartDate: date(16), PeriodEndDate: date(30), Transactions: []model.Transaction{
+			{PostingDate: date(16), Description: "DUNKIN #343475", Amount: 3.64},
+			{PostingDate: date(17), Description: "DUNKIN #343475", Amount: 3.64},
+		}},
+	}
+
+	merged, report := Merge(statements, Policy{})
+	if len(report.Duplicates) != 0 {
+		t.Errorf("Merge() duplicates = %+v, want none", report.Duplicates)
+	}
+	for i := range statements {
+		if len(merged[i].Transactions) != len(statements[i].Transactions) {
+			t.Errorf("Merge() statement %d has %d transactions, want %d", i, len(merged[i].Transactions), len(statements[i].Transactions))
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package dedup
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/muly/bank-tx/model"
+)
+
+func date(day int) time.Time {
+	return time.Date(2023, 4, day, 0, 0, 0, 0, time.UTC)
+}
+
+func ExampleMerge() {
+	statement := model.Statement{
+		Institution:     model.TD,
+		AccountNumber:   "123-4567890",
+		PeriodStartDate: date(1),
+		PeriodEndDate:   date(20),
+		Transactions: []model.Transaction{
+			{PostingDate: date(3), Description: "ACH DEPOSIT, rere erer ererereL", Amount: 6377.30},
+			{PostingDate: date(3), Description: "ACH DEPOSIT, rere erer ererereL", Amount: 6377.30},
+			{PostingDate: date(11), Description: "TD BILL PAY SERV, BANK OF AMERICA ONLINE PMT", Amount: -500},
+		},
+	}
+	// the csv download has the same deposits, the bill payment a day later and a transaction after the statement
+	download := model.Statement{
+		Institution:   model.TD,
+		AccountNumber: "123-4567890",
+		Transactions: []model.Transaction{
+			{PostingDate: date(3), Description: "ACH DEPOSIT RERE ERER ERERERE L", Amount: 6377.30},
+			{PostingDate: date(3), Description: "ACH DEPOSIT RERE ERER ERERERE L", Amount: 6377.30},
+			{PostingDate: date(12), Description: "TD BILL PAY SERV BANK OF AMERICA", Amount: -500},
+			{PostingDate: date(21), Description: "ACH DEPOSIT RERE ERER ERERERE L", Amount: 6377.30},
+		},
+	}
+
+	merged, report := Merge([]model.Statement{statement, download}, Policy{})
+	if err := report.Write(os.Stdout); err != nil {
+		fmt.Println(err)
+	}
+	for _, tx := range merged[1].Transactions {
+		fmt.Println("new:", tx.PostingDate.Format("2006-01-02"), tx.Description, tx.Amount)
+	}
+
+	// Output:
+	// KIND   KEPT                                                        DUPLICATE                                       AMOUNT   DRIFT  SIMILARITY
+	// fuzzy  #0 2023-04-03 ACH DEPOSIT, rere erer ererereL               #1 2023-04-03 ACH DEPOSIT RERE ERER ERERERE L   6377.30  0      0.80
+	// fuzzy  #0 2023-04-03 ACH DEPOSIT, rere erer ererereL               #1 2023-04-03 ACH DEPOSIT RERE ERER ERERERE L   6377.30  0      0.80
+	// fuzzy  #0 2023-04-11 TD BILL PAY SERV, BANK OF AMERICA ONLINE PMT  #1 2023-04-12 TD BILL PAY SERV BANK OF AMERICA  -500.00  1      1.00
+	// new: 2023-04-21 ACH DEPOSIT RERE ERER ERERERE L 6377.3
+}
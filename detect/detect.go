@@ -0,0 +1,204 @@
+// package detect provides the functions to detect the format of a statement file from its content and
+// to parse it with the matching parser into the bank neutral model
+package detect
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/muly/bank-tx/amex"
+	"github.com/muly/bank-tx/bofa_cc"
+	"github.com/muly/bank-tx/capital_one"
+	"github.com/muly/bank-tx/chase"
+	"github.com/muly/bank-tx/citi"
+	"github.com/muly/bank-tx/discover"
+	"github.com/muly/bank-tx/model"
+	"github.com/muly/bank-tx/ofx"
+	"github.com/muly/bank-tx/qif"
+	"github.com/muly/bank-tx/td"
+	"github.com/muly/bank-tx/wells_fargo"
+)
+
+// Version of the parsers, bump it when a parser changes the way it reads the statements so that the files
+// imported by the previous version are imported again
+const Version = "2"
+
+// Formats
+const (
+	TD         = "td"
+	BofACC     = "bofa_cc"
+	Chase      = "chase"
+	Amex       = "amex"
+	CapitalOne = "capital_one"
+	Discover   = "discover"
+	Citi       = "citi"
+	WellsFargo = "wells_fargo"
+	OFX        = "ofx"
+	QIF        = "qif"
+)
+
+type format struct {
+	name  string
+	match func(data string) bool
+	parse func(data string) ([]model.Statement, error)
+}
+
+// formats in the order they are tried, the file formats first as the statement markers could be in their descriptions
+var formats = []format{
+	{
+		name: OFX,
+		match: func(data string) bool {
+			return strings.Contains(data, "OFXHEADER") || strings.Contains(data, "<OFX>")
+		},
+		parse: func(data string) ([]model.Statement, error) {
+			return ofx.ReadStatements(strings.NewReader(data))
+		},
+	},
+	{
+		name: QIF,
+		match: func(data string) bool {
+			data = strings.TrimSpace(data)
+			return strings.HasPrefix(data, "!Type:") || strings.HasPrefix(data, "!Account") || strings.HasPrefix(data, "!Option:AutoSwitch")
+		},
+		parse: func(data string) ([]model.Statement, error) {
+			s, err := qif.ReadStatement(strings.NewReader(data), "")
+			if err != nil {
+				return nil, err
+			}
+			// the account is named by the !Account block only, without it the statements of all the accounts would merge
+			if s.AccountNumber == "" {
+				return nil, fmt.Errorf("qif without an !Account block naming the account")
+			}
+			return []model.Statement{*s}, nil
+		},
+	},
+	{
+		name:  Amex,
+		match: containsAll("American Express", "Account Ending", "Closing Date"),
+		parse: func(data string) ([]model.Statement, error) {
+			s, err := amex.ParseStatement(data)
+			if err != nil {
+				return nil, err
+			}
+			return []model.Statement{s.ToModel()}, nil
+		},
+	},
+	{
+		name:  CapitalOne,
+		match: containsAll("Capital One", "Billing Cycle"),
+		parse: func(data string) ([]model.Statement, error) {
+			s, err := capital_one.ParseStatement(data)
+			if err != nil {
+				return nil, err
+			}
+			return []model.Statement{s.ToModel()}, nil
+		},
+	},
+	{
+		name:  Discover,
+		match: containsAll("DISCOVER", "OPEN TO CLOSE DATE:"),
+		parse: func(data string) ([]model.Statement, error) {
+			s, err := discover.ParseStatement(data)
+			if err != nil {
+				return nil, err
+			}
+			return []model.Statement{s.ToModel()}, nil
+		},
+	},
+	{
+		name:  Citi,
+		match: containsAll("Citi", "Billing Period:"),
+		parse: func(data string) ([]model.Statement, error) {
+			s, err := citi.ParseStatement(data)
+			if err != nil {
+				return nil, err
+			}
+			return []model.Statement{s.ToModel()}, nil
+		},
+	},
+	{
+		name:  WellsFargo,
+		match: containsAll("Wells Fargo", "Statement period activity summary"),
+		parse: func(data string) ([]model.Statement, error) {
+			s, err := wells_fargo.ParseStatement(data)
+			if err != nil {
+				return nil, err
+			}
+			return []model.Statement{s.ToModel()}, nil
+		},
+	},
+	{
+		name:  Chase,
+		match: containsAll("Chase", "TRANSACTION DETAIL"),
+		parse: func(data string) ([]model.Statement, error) {
+			s, err := chase.ParseStatement(data)
+			if err != nil {
+				return nil, err
+			}
+			return []model.Statement{s.ToModel()}, nil
+		},
+	},
+	{
+		name:  TD,
+		match: containsAll("Statement Period:", "DAILY ACCOUNT ACTIVITY"),
+		parse: func(data string) ([]model.Statement, error) {
+			s, err := td.ParseStatement(data)
+			if err != nil {
+				return nil, err
+			}
+			return []model.Statement{s.ToModel()}, nil
+		},
+	},
+	{
+		name:  BofACC,
+		match: containsAll("Account#", "Previous Balance", "New Balance Total"),
+		parse: func(data string) ([]model.Statement, error) {
+			s, err := bofa_cc.ParseStatement(data)
+			if err != nil {
+				return nil, err
+			}
+			return []model.Statement{s.ToModel()}, nil
+		},
+	},
+}
+
+// Format returns the format of the statement file, e.g. TD or OFX
+func Format(data string) (string, error) {
+	for _, f := range formats {
+		if f.match(data) {
+			return f.name, nil
+		}
+	}
+	return "", fmt.Errorf("unknown statement format")
+}
+
+// Parse detects the format of the statement file and parses it, ofx files can have more than one statement.
+// The transactions get their stable IDs assigned.
+func Parse(data string) ([]model.Statement, string, error) {
+	for _, f := range formats {
+		if !f.match(data) {
+			continue
+		}
+		statements, err := f.parse(data)
+		if err != nil {
+			return nil, f.name, fmt.Errorf("failed to parse %s statement: %v", f.name, err)
+		}
+		for i := range statements {
+			statements[i].AssignIDs()
+		}
+		return statements, f.name, nil
+	}
+	return nil, "", fmt.Errorf("unknown statement format")
+}
+
+// containsAll returns a matcher for the data having all the markers
+func containsAll(markers ...string) func(data string) bool {
+	return func(data string) bool {
+		for _, marker := range markers {
+			if !strings.Contains(data, marker) {
+				return false
+			}
+		}
+		return true
+	}
+}
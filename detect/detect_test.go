@@ -0,0 +1,85 @@
+package detect
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		file   string
+		format string
+	}{
+		{file: "../td/sample.txt", format: TD},
+		{file: "../chase/sample.txt", format: Chase},
+		{file: "../amex/sample.txt", format: Amex},
+		{file: "../capital_one/sample.txt", format: CapitalOne},
+		{file: "../discover/sample.txt", format: Discover},
+		{file: "../citi/sample.txt", format: Citi},
+		{file: "../wells_fargo/sample.txt", format: WellsFargo},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			data, err := os.ReadFile(tt.file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			statements, format, err := Parse(string(data))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if format != tt.format {
+				t.Errorf("expected format %s, got %s", tt.format, format)
+			}
+			if len(statements) != 1 || len(statements[0].Transactions) == 0 {
+				t.Errorf("expected one statement with transactions, got %+v", statements)
+			}
+		})
+	}
+}
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		name   string
+		data   string
+		format string
+	}{
+		{name: "bofa_cc", data: "Account# 4400 1234 5678 1234\nSeptember 12 - October 11, 2024\nPrevious Balance $1,905.57\nNew Balance Total $42.75\n", format: BofACC},
+		{name: "ofx sgml", data: "OFXHEADER:100\nDATA:OFXSGML\n<OFX>\n", format: OFX},
+		{name: "ofx xml", data: "<?xml version=\"1.0\"?>\n<?OFX OFXHEADER=\"200\"?>\n<OFX></OFX>\n", format: OFX},
+		{name: "qif", data: "!Type:Bank\nD03/21'23\nT10.00\n^\n", format: QIF},
+		{name: "qif account", data: "!Account\nNTD Checking\nTBank\n^\n!Type:Bank\nD03/21'23\nT10.00\n^\n", format: QIF},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			format, err := Format(tt.data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if format != tt.format {
+				t.Errorf("expected format %s, got %s", tt.format, format)
+			}
+		})
+	}
+
+	if _, err := Format("hello"); err == nil {
+		t.Error("expected an error for the unknown format")
+	}
+}
+
+func TestParse_qif(t *testing.T) {
+	statements, format, err := Parse("!Account\nNTD Checking\nTBank\n^\n!Type:Bank\nD03/21'23\nT10.00\nPACH DEPOSIT\n^\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if format != QIF || len(statements) != 1 || statements[0].AccountNumber != "TD Checking" {
+		t.Errorf("expected the qif statement of TD Checking, got %s %+v", format, statements)
+	}
+
+	if _, _, err := Parse("!Type:Bank\nD03/21'23\nT10.00\nPACH DEPOSIT\n^\n"); err == nil || !strings.Contains(err.Error(), "!Account") {
+		t.Errorf("expected the missing account error, got %v", err)
+	}
+}
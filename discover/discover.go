@@ -0,0 +1,237 @@
+// package discover provides the parsing functions to process the discover credit card statements
+package discover
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/muly/bank-tx/model"
+	"github.com/muly/bank-tx/util"
+)
+
+// merchantCategories are the categories discover prints between the description and the amount
+var merchantCategories = []string{
+	"Awards and Rebate Credits",
+	"Department Stores",
+	"Education",
+	"Gasoline",
+	"Government Services",
+	"Home Improvement",
+	"Medical Services",
+	"Merchandise",
+	"Payments and Credits",
+	"Restaurants",
+	"Services",
+	"Supermarkets",
+	"Travel/ Entertainment",
+	"Warehouse Clubs",
+}
+
+type Transaction struct {
+	TransactionDate  time.Time
+	PostingDate      time.Time
+	Description      string
+	MerchantCategory string
+	Amount           float64
+	Category         string
+}
+
+type Statement struct {
+	AccountNumber    string
+	PeriodStartDate  time.Time
+	PeriodEndDate    time.Time
+	BeginningBalance float64
+	EndingBalance    float64
+	Transactions     []Transaction
+}
+
+var (
+	reAccount     = regexp.MustCompile(`^Account number ending in (\d{4})$`)
+	rePeriod      = regexp.MustCompile(`^OPEN TO CLOSE DATE: (\d{2}/\d{2}/\d{4}) - (\d{2}/\d{2}/\d{4})$`)
+	reSummary     = regexp.MustCompile(`^(Previous Balance|Payments and Credits|Purchases|Balance Transfers|Cash Advances|Fees Charged|Interest Charged|New Balance) ([-+]?\$[\d,]+\.\d{2})$`)
+	reTransaction = regexp.MustCompile(`^(\d{2}/\d{2})\s+(\d{2}/\d{2})\s+(.+?)\s+(-?\$[\d,]+\.\d{2})$`)
+)
+
+// ParseStatement parses the statement
+func ParseStatement(data string) (*Statement, error) {
+	lines := strings.Split(data, "\n")
+
+	var statement Statement
+	summary := map[string]float64{}
+	inCategory := ""
+	var err error
+
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+
+		if line == "" {
+			continue
+		}
+
+		// Parse Account Number
+		if match := reAccount.FindStringSubmatch(line); match != nil {
+			statement.AccountNumber = match[1]
+			continue
+		}
+
+		// Parse Statement Period
+		if match := rePeriod.FindStringSubmatch(line); match != nil {
+			statement.PeriodStartDate, statement.PeriodEndDate, err = parseStatementPeriod(match[1], match[2])
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		// Parse the account summary
+		if match := reSummary.FindStringSubmatch(line); match != nil {
+			summary[match[1]], err = util.ParseFloat(match[2])
+			if err != nil {
+				return nil, fmt.Errorf("%s parse error: %v", match[1], err)
+			}
+			continue
+		}
+
+		if line == "PAYMENTS AND CREDITS" ||
+			line == "PURCHASES" ||
+			line == "FEES" ||
+			line == "INTEREST CHARGED" {
+			inCategory = line
+			continue // Skip the header line
+		}
+
+		if line == "TRANS. DATE POST DATE PURCHASES MERCHANT CATEGORY AMOUNT" ||
+			line == "TRANSACTIONS" ||
+			line == "ACCOUNT SUMMARY" {
+			continue // Skip the header line
+		}
+
+		if strings.HasPrefix(line, "TOTAL ") {
+			continue // Skip the transaction subtotal line
+		}
+
+		transaction, err := ParseTransaction(line, statement.PeriodStartDate, statement.PeriodEndDate)
+		if err != nil {
+			return nil, err
+		}
+		if transaction == nil {
+			log.Printf("unprocessed line: %v", line)
+			continue // unwanted line
+		}
+
+		transaction.Category = inCategory
+
+		statement.Transactions = append(statement.Transactions, *transaction)
+	}
+
+	statement.BeginningBalance = summary["Previous Balance"]
+	statement.EndingBalance = summary["New Balance"]
+
+	// Validate balances
+	calculatedEndBalance := summary["Previous Balance"] + summary["Payments and Credits"] + summary["Purchases"] + summary["Balance Transfers"] +
+		summary["Cash Advances"] + summary["Fees Charged"] + summary["Interest Charged"]
+	if util.RoundToTwoDecimal(calculatedEndBalance) != util.RoundToTwoDecimal(statement.EndingBalance) {
+		return nil, fmt.Errorf("summary balance validation failed: calculated new balance %.2f, expected new balance %.2f", calculatedEndBalance, statement.EndingBalance)
+	}
+
+	total := calculateTotal(statement.Transactions)
+	if util.RoundToTwoDecimal(statement.BeginningBalance+total) != util.RoundToTwoDecimal(statement.EndingBalance) {
+		return nil, fmt.Errorf("tx balance validation failed. calculated end balance %v, expected end balance %v", util.RoundToTwoDecimal(statement.BeginningBalance+total), statement.EndingBalance)
+	}
+
+	return &statement, nil
+}
+
+// ParseTransaction parses the given transaction entry
+func ParseTransaction(line string, startPeriod, endPeriod time.Time) (*Transaction, error) {
+	matches := reTransaction.FindStringSubmatch(line)
+	if matches == nil {
+		return nil, nil
+	}
+
+	amount, err := util.ParseFloat(matches[4])
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse amount: %s, error: %v", matches[4], err)
+	}
+
+	transaction := Transaction{
+		Amount: amount,
+	}
+	transaction.Description, transaction.MerchantCategory = splitMerchantCategory(matches[3])
+
+	transaction.TransactionDate, err = util.AddYearToDate(matches[1], "01/02", startPeriod, endPeriod)
+	if err != nil {
+		return nil, fmt.Errorf("error adding year to transaction date: %v", err)
+	}
+	transaction.PostingDate, err = util.AddYearToDate(matches[2], "01/02", startPeriod, endPeriod)
+	if err != nil {
+		return nil, fmt.Errorf("error adding year to posting date: %v", err)
+	}
+
+	return &transaction, nil
+}
+
+// ToModel converts the statement to the bank neutral model
+func (s Statement) ToModel() model.Statement {
+	transactions := make([]model.Transaction, 0, len(s.Transactions))
+	for _, tx := range s.Transactions {
+		transactions = append(transactions, model.Transaction{
+			TransactionDate: tx.TransactionDate,
+			PostingDate:     tx.PostingDate,
+			Description:     tx.Description,
+			Amount:          tx.Amount,
+			Category:        tx.Category,
+		})
+	}
+
+	return model.Statement{
+		Institution:      model.Discover,
+		AccountType:      model.CreditCard,
+		AccountNumber:    s.AccountNumber,
+		PeriodStartDate:  s.PeriodStartDate,
+		PeriodEndDate:    s.PeriodEndDate,
+		BeginningBalance: s.BeginningBalance,
+		EndingBalance:    s.EndingBalance,
+		Transactions:     transactions,
+	}
+}
+
+// Helper functions
+
+func parseStatementPeriod(start, end string) (time.Time, time.Time, error) {
+	startPeriod, err := time.Parse("01/02/2006", start)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("failed to parse start period: %v", err)
+	}
+	endPeriod, err := time.Parse("01/02/2006", end)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("failed to parse end period: %v", err)
+	}
+	return startPeriod, endPeriod, nil
+}
+
+// splitMerchantCategory separates the trailing merchant category from the description
+func splitMerchantCategory(s string) (string, string) {
+	found := ""
+	for _, category := range merchantCategories {
+		// prefer the longest match, so that "Medical Services" wins over "Services"
+		if strings.HasSuffix(s, " "+category) && len(category) > len(found) {
+			found = category
+		}
+	}
+	if found == "" {
+		return s, ""
+	}
+	return strings.TrimSuffix(s, " "+found), found
+}
+
+func calculateTotal(transactions []Transaction) float64 {
+	total := 0.0
+	for _, tx := range transactions {
+		total += tx.Amount
+	}
+	return total
+}
@@ -0,0 +1,42 @@
+package discover
+
+import "testing"
+
+func Test_splitMerchantCategory(t *testing.T) {
+	tests := []struct {
+		name            string
+		s               string
+		wantDescription string
+		wantCategory    string
+	}{
+		{
+			name:            "case 1: category suffix",
+			s:               "COSTCO WHSE #1111 TOWN ST Warehouse Clubs",
+			wantDescription: "COSTCO WHSE #1111 TOWN ST",
+			wantCategory:    "Warehouse Clubs",
+		},
+		{
+			name:            "case 2: longest category wins",
+			s:               "MY HEALTH CLINIC TOWN ST Medical Services",
+			wantDescription: "MY HEALTH CLINIC TOWN ST",
+			wantCategory:    "Medical Services",
+		},
+		{
+			name:            "case 3: no category",
+			s:               "SOME MERCHANT TOWN ST",
+			wantDescription: "SOME MERCHANT TOWN ST",
+			wantCategory:    "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, got1 := splitMerchantCategory(tt.s)
+			if got != tt.wantDescription {
+				t.Errorf("splitMerchantCategory() got = %v, want %v", got, tt.wantDescription)
+			}
+			if got1 != tt.wantCategory {
+				t.Errorf("splitMerchantCategory() got1 = %v, want %v", got1, tt.wantCategory)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package discover
+
+import (
+	"fmt"
+)
+
+func ExampleParseStatement() {
+	statement := `
+DISCOVER
+Discover it Card
+Account number ending in 5678
+OPEN TO CLOSE DATE: 12/15/2023 - 01/14/2024
+
+ACCOUNT SUMMARY
+Previous Balance $543.21
+Payments and Credits -$543.21
+Purchases +$312.40
+Balance Transfers +$0.00
+Cash Advances +$0.00
+Fees Charged +$0.00
+Interest Charged +$0.00
+New Balance $312.40
+
+TRANSACTIONS
+TRANS. DATE POST DATE PURCHASES MERCHANT CATEGORY AMOUNT
+PAYMENTS AND CREDITS
+01/02 01/02 INTERNET PAYMENT - THANK YOU Payments and Credits -$543.21
+PURCHASES
+12/16 12/17 COSTCO WHSE #1111 TOWN ST Warehouse Clubs $145.67
+12/28 12/29 SHELL OIL 12345 TOWN ST Gasoline $45.23
+01/05 01/06 NETFLIX.COM LOS GATOS CA Services $15.49
+01/10 01/11 WHOLEFDS CAR 1111 TOWN ST Supermarkets $106.01
+FEES
+TOTAL FEES FOR THIS PERIOD $0.00
+INTEREST CHARGED
+TOTAL INTEREST FOR THIS PERIOD $0.00
+	`
+
+	s, err := ParseStatement(statement)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	for _, tx := range s.Transactions {
+		fmt.Printf("%+v\n", tx)
+	}
+
+	// Output:
+	// {TransactionDate:2024-01-02 00:00:00 +0000 UTC PostingDate:2024-01-02 00:00:00 +0000 UTC Description:INTERNET PAYMENT - THANK YOU MerchantCategory:Payments and Credits Amount:-543.21 Category:PAYMENTS AND CREDITS}
+	// {TransactionDate:2023-12-16 00:00:00 +0000 UTC PostingDate:2023-12-17 00:00:00 +0000 UTC Description:COSTCO WHSE #1111 TOWN ST MerchantCategory:Warehouse Clubs Amount:145.67 Category:PURCHASES}
+	// {TransactionDate:2023-12-28 00:00:00 +0000 UTC PostingDate:2023-12-29 00:00:00 +0000 UTC Description:SHELL OIL 12345 TOWN ST MerchantCategory:Gasoline Amount:45.23 Category:PURCHASES}
+	// {TransactionDate:2024-01-05 00:00:00 +0000 UTC PostingDate:2024-01-06 00:00:00 +0000 UTC Description:NETFLIX.COM LOS GATOS CA MerchantCategory:Services Amount:15.49 Category:PURCHASES}
+	// {TransactionDate:2024-01-10 00:00:00 +0000 UTC PostingDate:2024-01-11 00:00:00 +0000 UTC Description:WHOLEFDS CAR 1111 TOWN ST MerchantCategory:Supermarkets Amount:106.01 Category:PURCHASES}
+}
@@ -1,3 +1,8 @@
 module github.com/muly/bank-tx
 
 go 1.22.1
+
+require (
+	github.com/mattn/go-sqlite3 v1.14.22
+	gopkg.in/yaml.v3 v3.0.1
+)
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/muly/bank-tx/store"
+)
+
+// runHistory lists the import history and the months missing a statement per account
+func runHistory(args []string) error {
+	flags := flag.NewFlagSet("history", flag.ExitOnError)
+	db := flags.String("db", "bank-tx.db", "sqlite database file")
+	flags.Parse(args)
+
+	s, err := store.Open(*db)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+
+	imports, err := s.Imports()
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "IMPORTED\tFILE\tFORMAT\tPARSER\tSHA256\tSTATUS\tSTATEMENTS")
+	for _, i := range imports {
+		status := i.Status
+		if i.Error != "" {
+			status += ": " + i.Error
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%d\n", i.ImportedAt.Local().Format("2006-01-02 15:04"), i.Path, i.Format,
+			i.ParserVersion, i.SHA256[:12], status, i.Statements)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	accounts, err := s.MissingMonths()
+	if err != nil {
+		return err
+	}
+
+	fmt.Println()
+	w = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "INSTITUTION\tACCOUNT\tMISSING MONTHS")
+	for _, account := range accounts {
+		missing := strings.Join(account.Missing, ", ")
+		if missing == "" {
+			missing = "none"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", account.Institution, account.AccountNumber, missing)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/muly/bank-tx/detect"
+	"github.com/muly/bank-tx/store"
+	"github.com/muly/bank-tx/transfer"
+)
+
+// runImport parses the statement files, detecting their format, and adds them to the store.
+// The files already imported by the same parser version are skipped and the files changed since their last import are flagged.
+// The transfers between the accounts are marked after the files are imported.
+func runImport(args []string) error {
+	flags := flag.NewFlagSet("import", flag.ExitOnError)
+	db := flags.String("db", "bank-tx.db", "sqlite database file")
+	force := flags.Bool("force", false, "import the unchanged files again")
+	transfers := flags.String("transfers", "", "yaml or json transfer rules file, the default rules match the card payments")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: bank-tx import [-db file] [-force] [-transfers file] path...")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return fmt.Errorf("no files to import")
+	}
+
+	c, err := loadTransfers(*transfers)
+	if err != nil {
+		return err
+	}
+
+	files, err := listFiles(flags.Args()...)
+	if err != nil {
+		return err
+	}
+
+	s, err := store.Open(*db)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+
+	failed := 0
+	for _, file := range files {
+		ok, err := importFile(s, file, *force)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			failed++
+		}
+	}
+
+	// the other side of a transfer may be in a statement imported earlier, so the transfers are matched across the store
+	pairs, err := s.MarkTransfers(c)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%d transfers between the accounts\n", len(pairs))
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d files failed to import", failed, len(files))
+	}
+	return nil
+}
+
+// loadTransfers loads the transfer rules file, the default rules are used without one
+func loadTransfers(filename string) (transfer.Config, error) {
+	if filename == "" {
+		return transfer.DefaultConfig(), nil
+	}
+	return transfer.LoadConfig(filename)
+}
+
+// importFile imports the statements of the file and records the import in the history.
+// It returns false when the file could not be parsed, the error is for the failures of the store.
+func importFile(s *store.Store, file string, force bool) (bool, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return false, err
+	}
+
+	record := store.Import{Path: file, SHA256: store.Hash(data), ParserVersion: detect.Version}
+
+	if !force {
+		imported, err := s.ImportedOK(record.SHA256, record.ParserVersion)
+		if err != nil {
+			return false, err
+		}
+		if imported {
+			fmt.Printf("%s: unchanged, skipped\n", file)
+			return true, nil
+		}
+	}
+
+	last, err := s.LastImport(file)
+	if err != nil {
+		return false, err
+	}
+	if last != nil && last.SHA256 != record.SHA256 {
+		fmt.Printf("%s: changed since its import on %s\n", file, last.ImportedAt.Local().Format("2006-01-02 15:04"))
+	}
+
+	statements, format, err := detect.Parse(string(data))
+	record.Format = format
+	if err != nil {
+		fmt.Printf("%s: %v\n", file, err)
+		record.Status, record.Error = store.ImportFailed, err.Error()
+		_, err := s.RecordImport(record)
+		return false, err
+	}
+
+	for _, statement := range statements {
+		result, err := s.ImportStatement(statement)
+		if err != nil {
+			record.Status, record.Error = store.ImportFailed, err.Error()
+			s.RecordImport(record)
+			return false, fmt.Errorf("%s: %v", file, err)
+		}
+		action := "updated"
+		if result.Created {
+			action = "added"
+		}
+		fmt.Printf("%s: %s %s %s to %s, %d transactions %s\n", file, format, statement.AccountNumber,
+			statement.PeriodStartDate.Format("2006-01-02"), statement.PeriodEndDate.Format("2006-01-02"), result.Transactions, action)
+	}
+
+	record.Status, record.Statements = store.ImportOK, len(statements)
+	_, err = s.RecordImport(record)
+	return true, err
+}
@@ -0,0 +1,61 @@
+package journal
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/muly/bank-tx/model"
+)
+
+// WriteBeancount writes the statements as a beancount file, opening every account on the date it is first used.
+// The reference number and the transaction date are written as the "ref" and "transaction-date" metadata.
+// Beancount checks the balance at the start of the day, so the ending balance is asserted on the day after the period.
+func WriteBeancount(w io.Writer, statements []model.Statement, c Config) error {
+	commodity := c.Commodity
+	if commodity == "" {
+		commodity = "USD"
+	}
+
+	j := build(statements, c)
+	bw := bufio.NewWriter(w)
+
+	accounts := make([]string, 0, len(j.accounts))
+	for account := range j.accounts {
+		accounts = append(accounts, account)
+	}
+	sort.Strings(accounts)
+	for _, account := range accounts {
+		fmt.Fprintf(bw, "%s open %s %s\n", j.accounts[account].Format("2006-01-02"), account, commodity)
+	}
+
+	for _, item := range j.items {
+		fmt.Fprintln(bw)
+		switch item := item.(type) {
+		case entry:
+			fmt.Fprintf(bw, "%s * %s\n", item.date.Format("2006-01-02"), beancountString(item.description))
+			if item.reference != "" {
+				fmt.Fprintf(bw, "  ref: %s\n", beancountString(item.reference))
+			}
+			if !item.auxiliaryDate.IsZero() && !item.auxiliaryDate.Equal(item.date) {
+				fmt.Fprintf(bw, "  transaction-date: %s\n", item.auxiliaryDate.Format("2006-01-02"))
+			}
+			fmt.Fprintf(bw, "  %-40s  %s %s\n", item.account, amount(item.amount), commodity)
+			fmt.Fprintf(bw, "  %s\n", item.counter)
+		case assertion:
+			date := item.date
+			if item.ending {
+				date = date.AddDate(0, 0, 1)
+			}
+			fmt.Fprintf(bw, "%s balance %-40s  %s %s\n", date.Format("2006-01-02"), item.account, amount(item.balance), commodity)
+		}
+	}
+	return bw.Flush()
+}
+
+// beancountString quotes the string, escaping the quotes and the backslashes
+func beancountString(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(strings.Join(strings.Fields(s), " ")) + `"`
+}
@@ -0,0 +1,105 @@
+package journal
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/muly/bank-tx/bofa_cc"
+	"github.com/muly/bank-tx/model"
+)
+
+const bofaStatement = `
+Account# 4400 1234 5678 1234
+September 12 - October 11, 2024
+Previous Balance $1,905.57
+Payments and Other Credits -$1,905.57
+Purchases and Adjustments $42.75
+Fees Charged $0.00
+Interest Charged $0.00
+New Balance Total $42.75
+Payments and Other Credits
+09/28 09/30 PAYMENT - THANK YOU 0027 1234 -1,905.57
+Purchases and Adjustments
+09/13 09/16 ERERE RERE COUNTY SCHOOL FDFDDF-DDFDFD DF 0881 1234 42.75
+`
+
+func exampleStatements() []model.Statement {
+	s, err := bofa_cc.ParseStatement(bofaStatement)
+	if err != nil {
+		panic(err)
+	}
+	next := s.ToModel()
+	next.PeriodStartDate, next.PeriodEndDate = next.PeriodEndDate.AddDate(0, 0, 1), next.PeriodEndDate.AddDate(0, 1, 0)
+	next.BeginningBalance, next.Transactions = next.EndingBalance, nil
+
+	return []model.Statement{s.ToModel(), next}
+}
+
+var exampleConfig = Config{
+	Accounts:   map[string]string{"4400 1234 5678 1234": "Liabilities:BofA"},
+	Categories: map[string]string{"Payments and Other Credits": "Assets:TD:Checking"},
+}
+
+func ExampleWriteLedger() {
+	if err := WriteLedger(os.Stdout, exampleStatements(), exampleConfig); err != nil {
+		fmt.Println(err)
+	}
+
+	// Output:
+	// 2024/09/12 * Opening balance
+	//     Liabilities:BofA                          $-1905.57
+	//     Equity:Opening-Balances
+	//
+	// 2024/09/16=2024/09/13 * ERERE RERE COUNTY SCHOOL FDFDDF-DDFDFD DF
+	//     ; ref: 0881
+	//     Liabilities:BofA                          $-42.75
+	//     Expenses:Unknown
+	//
+	// 2024/09/30=2024/09/28 * PAYMENT - THANK YOU
+	//     ; ref: 0027
+	//     Liabilities:BofA                          $1905.57
+	//     Assets:TD:Checking
+	//
+	// 2024/10/11 * Balance assertion
+	//     Liabilities:BofA                          $0 = $-42.75
+	//
+	// 2024/10/12 * Balance assertion
+	//     Liabilities:BofA                          $0 = $-42.75
+	//
+	// 2024/11/11 * Balance assertion
+	//     Liabilities:BofA                          $0 = $-42.75
+}
+
+func ExampleWriteBeancount() {
+	if err := WriteBeancount(os.Stdout, exampleStatements(), exampleConfig); err != nil {
+		fmt.Println(err)
+	}
+
+	// Output:
+	// 2024-09-30 open Assets:TD:Checking USD
+	// 2024-09-12 open Equity:Opening-Balances USD
+	// 2024-09-16 open Expenses:Unknown USD
+	// 2024-09-12 open Liabilities:BofA USD
+	//
+	// 2024-09-12 * "Opening balance"
+	//   Liabilities:BofA                          -1905.57 USD
+	//   Equity:Opening-Balances
+	//
+	// 2024-09-16 * "ERERE RERE COUNTY SCHOOL FDFDDF-DDFDFD DF"
+	//   ref: "0881"
+	//   transaction-date: 2024-09-13
+	//   Liabilities:BofA                          -42.75 USD
+	//   Expenses:Unknown
+	//
+	// 2024-09-30 * "PAYMENT - THANK YOU"
+	//   ref: "0027"
+	//   transaction-date: 2024-09-28
+	//   Liabilities:BofA                          1905.57 USD
+	//   Assets:TD:Checking
+	//
+	// 2024-10-12 balance Liabilities:BofA                          -42.75 USD
+	//
+	// 2024-10-12 balance Liabilities:BofA                          -42.75 USD
+	//
+	// 2024-11-12 balance Liabilities:BofA                          -42.75 USD
+}
@@ -0,0 +1,226 @@
+// package journal provides the functions to export the statements as plain text accounting journals,
+// ledger-cli/hledger journal entries and beancount transactions
+package journal
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/muly/bank-tx/model"
+	"github.com/muly/bank-tx/util"
+)
+
+// Config maps the statements and transactions to the journal accounts
+type Config struct {
+	// Accounts maps the statement account number to the journal account, e.g. "123-4567890": "Assets:TD:Checking"
+	Accounts map[string]string `json:"accounts"`
+	// Categories maps the transaction category to the counter account, e.g. "Interest Charged": "Expenses:Interest"
+	Categories map[string]string `json:"categories"`
+	// Expenses and Income are the counter accounts of the outflows and the inflows without a category mapping
+	Expenses string `json:"expenses"`
+	Income   string `json:"income"`
+	// Opening is the counter account of the opening balance of the first statement of each account
+	Opening string `json:"opening"`
+	// Commodity is the currency of the amounts, e.g. "$" for ledger and "USD" for beancount
+	Commodity string `json:"commodity"`
+}
+
+// DefaultConfig returns the config used for the fields missing in the config file
+func DefaultConfig() Config {
+	return Config{
+		Accounts:   map[string]string{},
+		Categories: map[string]string{},
+		Expenses:   "Expenses:Unknown",
+		Income:     "Income:Unknown",
+		Opening:    "Equity:Opening-Balances",
+	}
+}
+
+// LoadConfig loads the json config file
+func LoadConfig(filename string) (Config, error) {
+	c := DefaultConfig()
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return c, err
+	}
+	if err := json.Unmarshal(data, &c); err != nil {
+		return c, fmt.Errorf("failed to parse %s: %v", filename, err)
+	}
+	return c.withDefaults(), nil
+}
+
+func (c Config) withDefaults() Config {
+	d := DefaultConfig()
+	if c.Accounts == nil {
+		c.Accounts = d.Accounts
+	}
+	if c.Categories == nil {
+		c.Categories = d.Categories
+	}
+	if c.Expenses == "" {
+		c.Expenses = d.Expenses
+	}
+	if c.Income == "" {
+		c.Income = d.Income
+	}
+	if c.Opening == "" {
+		c.Opening = d.Opening
+	}
+	return c
+}
+
+// account returns the journal account of the statement, the unmapped checking accounts are assets and
+// the unmapped credit cards are liabilities
+func (c Config) account(s model.Statement) string {
+	if account, ok := c.Accounts[s.AccountNumber]; ok {
+		return account
+	}
+	root := "Assets"
+	if s.AccountType == model.CreditCard {
+		root = "Liabilities"
+	}
+	return accountName(root, strings.ToUpper(s.Institution), s.AccountNumber)
+}
+
+// counterAccount returns the account the other side of the transaction is posted to
+func (c Config) counterAccount(s model.Statement, tx model.Transaction) string {
+	if account, ok := c.Categories[tx.Category]; ok {
+		return account
+	}
+	if s.Flow(tx) < 0 {
+		return c.Expenses
+	}
+	return c.Income
+}
+
+// accountName joins the components of an account name, dropping the characters the journal formats do not allow
+func accountName(components ...string) string {
+	clean := make([]string, 0, len(components))
+	for _, component := range components {
+		component = strings.Join(strings.Fields(component), "-")
+		component = strings.Map(func(r rune) rune {
+			switch r {
+			case ':', ';', '"':
+				return -1
+			case '_':
+				return '-'
+			}
+			return r
+		}, component)
+		if component != "" {
+			clean = append(clean, component)
+		}
+	}
+	return strings.Join(clean, ":")
+}
+
+// posting is the signed amount posted to the statement account, the credit card balance is a liability
+// so its increase is a negative posting
+func posting(s model.Statement, amount float64) float64 {
+	if s.AccountType == model.CreditCard {
+		return -amount
+	}
+	return amount
+}
+
+// amount formats the amount with two decimals, without the negative zero
+func amount(v float64) string {
+	return fmt.Sprintf("%.2f", util.RoundToTwoDecimal(v)+0)
+}
+
+// entry is a journal transaction, the counter posting takes the balancing amount
+type entry struct {
+	date          time.Time
+	auxiliaryDate time.Time
+	description   string
+	reference     string
+	account       string
+	amount        float64
+	counter       string
+}
+
+// assertion is a balance assertion of the account at the start or at the end of a date
+type assertion struct {
+	date    time.Time
+	account string
+	balance float64
+	ending  bool
+}
+
+// journal holds the entries and the assertions of the statements in the order they are written
+type journal struct {
+	items []interface{}
+	// accounts holds the date each account is first used on
+	accounts map[string]time.Time
+}
+
+// build turns the statements into the opening balances, the balance assertions and the entries, by account and period
+func build(statements []model.Statement, c Config) journal {
+	c = c.withDefaults()
+	j := journal{accounts: map[string]time.Time{}}
+	opened := map[string]bool{}
+
+	// the opening balance and the assertions depend on the previous statement of the account, so the statements
+	// are taken by account and period whatever order they are given in
+	sorted := append([]model.Statement(nil), statements...)
+	sort.SliceStable(sorted, func(i, k int) bool {
+		a, b := c.account(sorted[i]), c.account(sorted[k])
+		if a != b {
+			return a < b
+		}
+		return sorted[i].PeriodStartDate.Before(sorted[k].PeriodStartDate)
+	})
+
+	use := func(account string, date time.Time) {
+		if first, ok := j.accounts[account]; !ok || date.Before(first) {
+			j.accounts[account] = date
+		}
+	}
+
+	for _, s := range sorted {
+		account := c.account(s)
+		use(account, s.PeriodStartDate)
+
+		// the first statement of the account opens it with the beginning balance, the later ones assert it
+		if !opened[account] {
+			opened[account] = true
+			use(c.Opening, s.PeriodStartDate)
+			j.items = append(j.items, entry{
+				date:        s.PeriodStartDate,
+				description: "Opening balance",
+				account:     account,
+				amount:      posting(s, s.BeginningBalance),
+				counter:     c.Opening,
+			})
+		} else {
+			j.items = append(j.items, assertion{date: s.PeriodStartDate, account: account, balance: posting(s, s.BeginningBalance)})
+		}
+
+		// the statements list the transactions by section, the journal needs them in date order for the assertions
+		transactions := append([]model.Transaction(nil), s.Transactions...)
+		sort.SliceStable(transactions, func(i, k int) bool {
+			return transactions[i].PostingDate.Before(transactions[k].PostingDate)
+		})
+
+		for _, tx := range transactions {
+			counter := c.counterAccount(s, tx)
+			use(counter, tx.PostingDate)
+			j.items = append(j.items, entry{
+				date:          tx.PostingDate,
+				auxiliaryDate: tx.TransactionDate,
+				description:   tx.Description,
+				reference:     tx.ReferenceNumber,
+				account:       account,
+				amount:        posting(s, tx.Amount),
+				counter:       counter,
+			})
+		}
+
+		j.items = append(j.items, assertion{date: s.PeriodEndDate, account: account, balance: posting(s, s.EndingBalance), ending: true})
+	}
+	return j
+}
@@ -0,0 +1,185 @@
+package journal
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/muly/bank-tx/model"
+)
+
+func date(month time.Month, day int) time.Time {
+	return time.Date(2024, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// statements returns two consecutive checking statements and a credit card statement, in no particular order
+func statements() []model.Statement {
+	return []model.Statement{
+		{
+			Institution: model.TD, AccountType: model.Checking, AccountNumber: "123-4567890",
+			PeriodStartDate: date(time.April, 21), PeriodEndDate: date(time.May, 20), BeginningBalance: 1100, EndingBalance: 1050,
+			Transactions: []model.Transaction{
+				{PostingDate: date(time.May, 2), Description: "TD BILL PAY SERV, BANK OF AMERICA ONLINE PMT", Amount: -50},
+			},
+		},
+		{
+			Institution: model.BofA, AccountType: model.CreditCard, AccountNumber: "4400 1234 5678 1234",
+			PeriodStartDate: date(time.April, 12), PeriodEndDate: date(time.May, 11), BeginningBalance: 50, EndingBalance: 30,
+			Transactions: []model.Transaction{
+				{TransactionDate: date(time.May, 3), PostingDate: date(time.May, 4), Description: "PAYMENT - THANK YOU", ReferenceNumber: "0027", Amount: -50, Category: "Payments and Other Credits"},
+				{TransactionDate: date(time.April, 28), PostingDate: date(time.April, 29), Description: "COSTCO WHSE #1111", ReferenceNumber: "0881", Amount: 30, Category: "Purchases and Adjustments"},
+			},
+		},
+		{
+			Institution: model.TD, AccountType: model.Checking, AccountNumber: "123-4567890",
+			PeriodStartDate: date(time.March, 21), PeriodEndDate: date(time.April, 20), BeginningBalance: 1000, EndingBalance: 1100,
+			Transactions: []model.Transaction{
+				{PostingDate: date(time.April, 2), Description: "ACH DEPOSIT, ACME CORP PAYROLL", Amount: 100},
+			},
+		},
+	}
+}
+
+var config = Config{
+	Accounts: map[string]string{
+		"123-4567890":         "Assets:TD:Checking",
+		"4400 1234 5678 1234": "Liabilities:BofA",
+	},
+	Categories: map[string]string{"Payments and Other Credits": "Assets:TD:Checking"},
+}
+
+func TestWriteLedger(t *testing.T) {
+	want := `2024/03/21 * Opening balance
+    Assets:TD:Checking                        $1000.00
+    Equity:Opening-Balances
+
+2024/04/02 * ACH DEPOSIT, ACME CORP PAYROLL
+    Assets:TD:Checking                        $100.00
+    Income:Unknown
+
+2024/04/20 * Balance assertion
+    Assets:TD:Checking                        $0 = $1100.00
+
+2024/04/21 * Balance assertion
+    Assets:TD:Checking                        $0 = $1100.00
+
+2024/05/02 * TD BILL PAY SERV, BANK OF AMERICA ONLINE PMT
+    Assets:TD:Checking                        $-50.00
+    Expenses:Unknown
+
+2024/05/20 * Balance assertion
+    Assets:TD:Checking                        $0 = $1050.00
+
+2024/04/12 * Opening balance
+    Liabilities:BofA                          $-50.00
+    Equity:Opening-Balances
+
+2024/04/29=2024/04/28 * COSTCO WHSE #1111
+    ; ref: 0881
+    Liabilities:BofA                          $-30.00
+    Expenses:Unknown
+
+2024/05/04=2024/05/03 * PAYMENT - THANK YOU
+    ; ref: 0027
+    Liabilities:BofA                          $50.00
+    Assets:TD:Checking
+
+2024/05/11 * Balance assertion
+    Liabilities:BofA                          $0 = $-30.00
+`
+
+	var b bytes.Buffer
+	if err := WriteLedger(&b, statements(), config); err != nil {
+		t.Fatal(err)
+	}
+	if b.String() != want {
+		t.Errorf("WriteLedger() =\n%s\nwant\n%s", b.String(), want)
+	}
+}
+
+func TestWriteBeancount(t *testing.T) {
+	want := `2024-03-21 open Assets:TD:Checking USD
+2024-03-21 open Equity:Opening-Balances USD
+2024-04-29 open Expenses:Unknown USD
+2024-04-02 open Income:Unknown USD
+2024-04-12 open Liabilities:BofA USD
+
+2024-03-21 * "Opening balance"
+  Assets:TD:Checking                        1000.00 USD
+  Equity:Opening-Balances
+
+2024-04-02 * "ACH DEPOSIT, ACME CORP PAYROLL"
+  Assets:TD:Checking                        100.00 USD
+  Income:Unknown
+
+2024-04-21 balance Assets:TD:Checking                        1100.00 USD
+
+2024-04-21 balance Assets:TD:Checking                        1100.00 USD
+
+2024-05-02 * "TD BILL PAY SERV, BANK OF AMERICA ONLINE PMT"
+  Assets:TD:Checking                        -50.00 USD
+  Expenses:Unknown
+
+2024-05-21 balance Assets:TD:Checking                        1050.00 USD
+
+2024-04-12 * "Opening balance"
+  Liabilities:BofA                          -50.00 USD
+  Equity:Opening-Balances
+
+2024-04-29 * "COSTCO WHSE #1111"
+  ref: "0881"
+  transaction-date: 2024-04-28
+  Liabilities:BofA                          -30.00 USD
+  Expenses:Unknown
+
+2024-05-04 * "PAYMENT - THANK YOU"
+  ref: "0027"
+  transaction-date: 2024-05-03
+  Liabilities:BofA                          50.00 USD
+  Assets:TD:Checking
+
+2024-05-12 balance Liabilities:BofA                          -30.00 USD
+`
+
+	var b bytes.Buffer
+	if err := WriteBeancount(&b, statements(), config); err != nil {
+		t.Fatal(err)
+	}
+	if b.String() != want {
+		t.Errorf("WriteBeancount() =\n%s\nwant\n%s", b.String(), want)
+	}
+}
+
+func TestBuild_assertionsAddUp(t *testing.T) {
+	// every assertion must hold for the postings before it, whatever order the statements are given in
+	for _, order := range [][]int{{0, 1, 2}, {2, 1, 0}, {1, 0, 2}} {
+		var input []model.Statement
+		for _, i := range order {
+			input = append(input, statements()[i])
+		}
+
+		balances := map[string]float64{}
+		for _, item := range build(input, config).items {
+			switch item := item.(type) {
+			case entry:
+				balances[item.account] += item.amount
+			case assertion:
+				if amount(balances[item.account]) != amount(item.balance) {
+					t.Errorf("order %v: %s on %s is %s, asserted %s", order, item.account, item.date.Format("2006-01-02"),
+						amount(balances[item.account]), amount(item.balance))
+				}
+			}
+		}
+	}
+}
+
+func TestLoadConfig_defaults(t *testing.T) {
+	c := Config{Accounts: config.Accounts}.withDefaults()
+	if c.Opening != "Equity:Opening-Balances" || c.Expenses != "Expenses:Unknown" || c.Income != "Income:Unknown" {
+		t.Errorf("withDefaults() = %+v", c)
+	}
+	if got := c.account(model.Statement{Institution: model.Chase, AccountType: model.CreditCard, AccountNumber: "9 9"}); !strings.HasPrefix(got, "Liabilities:CHASE") {
+		t.Errorf("account() of an unmapped card = %s, want Liabilities:CHASE:...", got)
+	}
+}
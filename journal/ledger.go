@@ -0,0 +1,54 @@
+package journal
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/muly/bank-tx/model"
+)
+
+// WriteLedger writes the statements as a journal readable by both ledger-cli and hledger.
+// The transaction date is written as the auxiliary date when it differs from the posting date and
+// the reference number as the "ref" tag. The ending balance of every statement, and the beginning balance
+// of every statement but the first of the account, are written as balance assertions.
+func WriteLedger(w io.Writer, statements []model.Statement, c Config) error {
+	commodity := c.Commodity
+	if commodity == "" {
+		commodity = "$"
+	}
+
+	bw := bufio.NewWriter(w)
+	for i, item := range build(statements, c).items {
+		if i > 0 {
+			fmt.Fprintln(bw)
+		}
+		switch item := item.(type) {
+		case entry:
+			date := item.date.Format("2006/01/02")
+			if !item.auxiliaryDate.IsZero() && !item.auxiliaryDate.Equal(item.date) {
+				date += "=" + item.auxiliaryDate.Format("2006/01/02")
+			}
+			fmt.Fprintf(bw, "%s * %s\n", date, ledgerPayee(item.description))
+			if item.reference != "" {
+				fmt.Fprintf(bw, "    ; ref: %s\n", item.reference)
+			}
+			fmt.Fprintf(bw, "    %-40s  %s%s\n", item.account, commodity, amount(item.amount))
+			fmt.Fprintf(bw, "    %s\n", item.counter)
+		case assertion:
+			fmt.Fprintf(bw, "%s * Balance assertion\n", item.date.Format("2006/01/02"))
+			fmt.Fprintf(bw, "    %-40s  %s0 = %s%s\n", item.account, commodity, commodity, amount(item.balance))
+		}
+	}
+	return bw.Flush()
+}
+
+// ledgerPayee collapses the spaces of the description, two spaces start a note in the payee
+func ledgerPayee(description string) string {
+	payee := strings.Join(strings.Fields(description), " ")
+	if payee == "" {
+		return "Unknown"
+	}
+	return payee
+}
@@ -0,0 +1,106 @@
+package jsonexport
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/muly/bank-tx/bofa_cc"
+)
+
+const bofaStatement = `
+Account# 4400 1234 5678 1234
+September 12 - October 11, 2024
+Previous Balance $1,905.57
+Payments and Other Credits -$1,905.57
+Purchases and Adjustments $42.75
+Fees Charged $0.00
+Interest Charged $0.00
+New Balance Total $42.75
+Payments and Other Credits
+09/28 09/30 PAYMENT - THANK YOU 0027 1234 -1,905.57
+Purchases and Adjustments
+09/13 09/16 ERERE RERE COUNTY SCHOOL FDFDDF-DDFDFD DF 0881 1234 42.75
+`
+
+func ExampleWriteStatement() {
+	s, err := bofa_cc.ParseStatement(bofaStatement)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	if err := WriteStatement(os.Stdout, s.ToModel(), Metadata{Source: "bofa-cc-2024-10-11.txt"}); err != nil {
+		fmt.Println(err)
+	}
+
+	// Output:
+	// {
+	//   "schema_version": "1.2.0",
+	//   "metadata": {
+	//     "source": "bofa-cc-2024-10-11.txt"
+	//   },
+	//   "statement": {
+	//     "institution": "bofa",
+	//     "account_type": "credit_card",
+	//     "account_number": "4400 1234 5678 1234",
+	//     "period_start_date": "2024-09-12",
+	//     "period_end_date": "2024-10-11",
+	//     "beginning_balance": 1905.57,
+	//     "ending_balance": 42.75,
+	//     "total": -1862.82,
+	//     "transactions": [
+	//       {
+	//         "transaction_date": "2024-09-28",
+	//         "posting_date": "2024-09-30",
+	//         "description": "PAYMENT - THANK YOU",
+	//         "reference_number": "0027",
+	//         "account_number": "1234",
+	//         "amount": -1905.57,
+	//         "category": "Payments and Other Credits"
+	//       },
+	//       {
+	//         "transaction_date": "2024-09-13",
+	//         "posting_date": "2024-09-16",
+	//         "description": "ERERE RERE COUNTY SCHOOL FDFDDF-DDFDFD DF",
+	//         "reference_number": "0881",
+	//         "account_number": "1234",
+	//         "amount": 42.75,
+	//         "category": "Purchases and Adjustments"
+	//       }
+	//     ]
+	//   },
+	//   "validation": {
+	//     "valid": true,
+	//     "checks": [
+	//       {
+	//         "name": "balance",
+	//         "passed": true,
+	//         "expected": 42.75,
+	//         "actual": 42.75
+	//       },
+	//       {
+	//         "name": "period",
+	//         "passed": true,
+	//         "expected": 0,
+	//         "actual": 0
+	//       }
+	//     ]
+	//   }
+	// }
+}
+
+func ExampleWriteTransactions() {
+	s, err := bofa_cc.ParseStatement(bofaStatement)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	if err := WriteTransactions(os.Stdout, s.ToModel()); err != nil {
+		fmt.Println(err)
+	}
+
+	// Output:
+	// {"schema_version":"1.2.0","institution":"bofa","account_type":"credit_card","statement_account_number":"4400 1234 5678 1234","period_start_date":"2024-09-12","period_end_date":"2024-10-11","transaction_date":"2024-09-28","posting_date":"2024-09-30","description":"PAYMENT - THANK YOU","reference_number":"0027","account_number":"1234","amount":-1905.57,"category":"Payments and Other Credits"}
+	// {"schema_version":"1.2.0","institution":"bofa","account_type":"credit_card","statement_account_number":"4400 1234 5678 1234","period_start_date":"2024-09-12","period_end_date":"2024-10-11","transaction_date":"2024-09-13","posting_date":"2024-09-16","description":"ERERE RERE COUNTY SCHOOL FDFDDF-DDFDFD DF","reference_number":"0881","account_number":"1234","amount":42.75,"category":"Purchases and Adjustments"}
+}
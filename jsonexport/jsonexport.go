@@ -0,0 +1,191 @@
+// package jsonexport provides the functions to write the statements as json and json lines, so the output can be
+// consumed by the scripting tools without re-parsing the csv.
+//
+// The documents follow a versioned schema, SchemaVersion is bumped on every change of the fields and the json schema
+// of each version is in statement.schema.json (WriteStatement) and transaction.schema.json (WriteTransactions).
+// Dates are written as "2006-01-02" and amounts as numbers signed the way they move the statement balance.
+package jsonexport
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/muly/bank-tx/model"
+	"github.com/muly/bank-tx/util"
+)
+
+// SchemaVersion is the version of the documents written by this package
+const SchemaVersion = "1.2.0"
+
+// StatementSchema and TransactionSchema are the json schema of the statement document and the transaction line
+var (
+	//go:embed statement.schema.json
+	StatementSchema []byte
+	//go:embed transaction.schema.json
+	TransactionSchema []byte
+)
+
+const dateLayout = "2006-01-02"
+
+// Metadata describes where the statement was read from and when it was exported, both are optional
+type Metadata struct {
+	Source     string
+	ExportedAt time.Time
+}
+
+// Check is the result of one validation of the statement
+type Check struct {
+	Name     string  `json:"name"`
+	Passed   bool    `json:"passed"`
+	Expected float64 `json:"expected"`
+	Actual   float64 `json:"actual"`
+}
+
+// Validation holds the checks of the statement, Valid is set when all of them pass
+type Validation struct {
+	Valid  bool    `json:"valid"`
+	Checks []Check `json:"checks"`
+}
+
+// Validate checks that the transactions add up to the ending balance and that they are posted within the statement period
+func Validate(s model.Statement) Validation {
+	validation := s.Validate()
+	v := Validation{Valid: validation.Valid, Checks: make([]Check, 0, len(validation.Checks))}
+	for _, check := range validation.Checks {
+		v.Checks = append(v.Checks, Check(check))
+	}
+	return v
+}
+
+type transaction struct {
+	ID              string  `json:"id,omitempty"`
+	TransactionDate string  `json:"transaction_date,omitempty"`
+	PostingDate     string  `json:"posting_date"`
+	Description     string  `json:"description"`
+	ReferenceNumber string  `json:"reference_number,omitempty"`
+	AccountNumber   string  `json:"account_number,omitempty"`
+	Amount          float64 `json:"amount"`
+	Category        string  `json:"category,omitempty"`
+	TxType          string  `json:"tx_type,omitempty"`
+	Counterparty    string  `json:"counterparty,omitempty"`
+}
+
+type statement struct {
+	Institution      string        `json:"institution"`
+	AccountType      string        `json:"account_type"`
+	AccountNumber    string        `json:"account_number"`
+	PeriodStartDate  string        `json:"period_start_date"`
+	PeriodEndDate    string        `json:"period_end_date"`
+	BeginningBalance float64       `json:"beginning_balance"`
+	EndingBalance    float64       `json:"ending_balance"`
+	Total            float64       `json:"total"`
+	Transactions     []transaction `json:"transactions"`
+}
+
+type statementDocument struct {
+	SchemaVersion string     `json:"schema_version"`
+	Metadata      metadata   `json:"metadata"`
+	Statement     statement  `json:"statement"`
+	Validation    Validation `json:"validation"`
+}
+
+type metadata struct {
+	Source     string `json:"source,omitempty"`
+	ExportedAt string `json:"exported_at,omitempty"`
+}
+
+type transactionLine struct {
+	SchemaVersion    string `json:"schema_version"`
+	Institution      string `json:"institution"`
+	AccountType      string `json:"account_type"`
+	StatementAccount string `json:"statement_account_number"`
+	PeriodStartDate  string `json:"period_start_date"`
+	PeriodEndDate    string `json:"period_end_date"`
+	transaction
+}
+
+// WriteStatement writes the statement with its metadata and validation results as an indented json document
+func WriteStatement(w io.Writer, s model.Statement, m Metadata) error {
+	doc := statementDocument{
+		SchemaVersion: SchemaVersion,
+		Metadata:      metadata{Source: m.Source, ExportedAt: formatTime(m.ExportedAt)},
+		Statement: statement{
+			Institution:      s.Institution,
+			AccountType:      s.AccountType,
+			AccountNumber:    s.AccountNumber,
+			PeriodStartDate:  formatDate(s.PeriodStartDate),
+			PeriodEndDate:    formatDate(s.PeriodEndDate),
+			BeginningBalance: s.BeginningBalance,
+			EndingBalance:    s.EndingBalance,
+			Total:            util.RoundToTwoDecimal(s.Total()),
+			Transactions:     make([]transaction, 0, len(s.Transactions)),
+		},
+		Validation: Validate(s),
+	}
+	for _, tx := range s.Transactions {
+		doc.Statement.Transactions = append(doc.Statement.Transactions, toTransaction(tx))
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return fmt.Errorf("failed to write statement %s: %v", s.AccountNumber, err)
+	}
+	return nil
+}
+
+// WriteTransactions writes one json object per line for every transaction of the statements,
+// each line carries the statement it belongs to
+func WriteTransactions(w io.Writer, statements ...model.Statement) error {
+	encoder := json.NewEncoder(w)
+	for _, s := range statements {
+		for _, tx := range s.Transactions {
+			line := transactionLine{
+				SchemaVersion:    SchemaVersion,
+				Institution:      s.Institution,
+				AccountType:      s.AccountType,
+				StatementAccount: s.AccountNumber,
+				PeriodStartDate:  formatDate(s.PeriodStartDate),
+				PeriodEndDate:    formatDate(s.PeriodEndDate),
+				transaction:      toTransaction(tx),
+			}
+			if err := encoder.Encode(line); err != nil {
+				return fmt.Errorf("failed to write transaction %q: %v", tx.Description, err)
+			}
+		}
+	}
+	return nil
+}
+
+func toTransaction(tx model.Transaction) transaction {
+	return transaction{
+		ID:              tx.ID,
+		TransactionDate: formatDate(tx.TransactionDate),
+		PostingDate:     formatDate(tx.PostingDate),
+		Description:     tx.Description,
+		ReferenceNumber: tx.ReferenceNumber,
+		AccountNumber:   tx.AccountNumber,
+		Amount:          tx.Amount,
+		Category:        tx.Category,
+		TxType:          tx.TxType,
+		Counterparty:    tx.Counterparty,
+	}
+}
+
+// formatDate formats the date, the zero date is left empty
+func formatDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(dateLayout)
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,99 @@
+package jsonexport
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/muly/bank-tx/model"
+)
+
+var testStatement = model.Statement{
+	Institution:      model.TD,
+	AccountType:      model.Checking,
+	AccountNumber:    "123-4567890",
+	PeriodStartDate:  time.Date(2023, 3, 21, 0, 0, 0, 0, time.UTC),
+	PeriodEndDate:    time.Date(2023, 4, 20, 0, 0, 0, 0, time.UTC),
+	BeginningBalance: 100,
+	EndingBalance:    50,
+	Transactions: []model.Transaction{
+		{PostingDate: time.Date(2023, 4, 2, 0, 0, 0, 0, time.UTC), Description: "ACH DEPOSIT", Amount: 25},
+		{PostingDate: time.Date(2023, 4, 22, 0, 0, 0, 0, time.UTC), Description: "TD BILL PAY SERV", Amount: -50},
+	},
+}
+
+func TestValidate(t *testing.T) {
+	v := Validate(testStatement)
+	if v.Valid {
+		t.Fatalf("expected invalid statement, got %+v", v)
+	}
+	expected := map[string]Check{
+		"balance": {Name: "balance", Passed: false, Expected: 50, Actual: 75},
+		"period":  {Name: "period", Passed: false, Expected: 0, Actual: 1},
+	}
+	for _, check := range v.Checks {
+		if check != expected[check.Name] {
+			t.Errorf("check %s: expected %+v, got %+v", check.Name, expected[check.Name], check)
+		}
+	}
+}
+
+// TestSchemaRequired checks that the documents have every field the json schema requires
+func TestSchemaRequired(t *testing.T) {
+	var schema struct {
+		Required   []string `json:"required"`
+		Properties map[string]struct {
+			Required []string `json:"required"`
+		} `json:"properties"`
+	}
+
+	var statementDoc bytes.Buffer
+	if err := WriteStatement(&statementDoc, testStatement, Metadata{}); err != nil {
+		t.Fatal(err)
+	}
+	var transactionDoc bytes.Buffer
+	if err := WriteTransactions(&transactionDoc, testStatement); err != nil {
+		t.Fatal(err)
+	}
+	firstLine, _, _ := bytes.Cut(transactionDoc.Bytes(), []byte("\n"))
+
+	tests := []struct {
+		name   string
+		schema []byte
+		doc    []byte
+		nested string
+	}{
+		{name: "statement", schema: StatementSchema, doc: statementDoc.Bytes(), nested: "statement"},
+		{name: "transaction", schema: TransactionSchema, doc: firstLine},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := json.Unmarshal(tt.schema, &schema); err != nil {
+				t.Fatalf("invalid schema: %v", err)
+			}
+			var doc map[string]json.RawMessage
+			if err := json.Unmarshal(tt.doc, &doc); err != nil {
+				t.Fatalf("invalid document: %v", err)
+			}
+			for _, field := range schema.Required {
+				if _, ok := doc[field]; !ok {
+					t.Errorf("missing required field %s", field)
+				}
+			}
+			if tt.nested == "" {
+				return
+			}
+			var nested map[string]json.RawMessage
+			if err := json.Unmarshal(doc[tt.nested], &nested); err != nil {
+				t.Fatalf("invalid %s: %v", tt.nested, err)
+			}
+			for _, field := range schema.Properties[tt.nested].Required {
+				if _, ok := nested[field]; !ok {
+					t.Errorf("missing required field %s.%s", tt.nested, field)
+				}
+			}
+		})
+	}
+}
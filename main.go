@@ -9,52 +9,109 @@ import (
 	bofacc "github.com/muly/bank-tx/bofa_cc"
 )
 
+const usage = `usage: bank-tx [command] [flags]
+
+commands:
+  import      parse the statement files and add them to the sqlite store
+  history     list the imported files and the months missing a statement per account
+  check       check the balances and the periods of the consecutive statements of every account
+  dedup       report the transactions found in more than one statement of the same account and remove them with -apply
+  categorize  set the spending category of the uncategorized transactions by the rules file, or of one transaction by hand
+  train       train the category classifier on the transactions categorized by hand and report its accuracy
+  suggest     suggest the category of the uncategorized transactions by the classifier and the rules
+  recurring   list the recurring charges and deposits with the price increases and the missed occurrences
+  report      report the spending by category, month and merchant as text, csv or markdown
+  cashflow    report the monthly income, outflow, savings rate, net position and balance range of every account
+
+without a command the bofa credit card statements in temp/bofa-cc/2023 are saved to temp/bofa-cc-2023.csv
+`
+
 func main() {
-	files := []string{}
-	err := filepath.WalkDir("./temp/bofa-cc/2023", func(path string, d fs.DirEntry, err error) error {
-		if err != nil {
-			return err
+	if len(os.Args) < 2 {
+		if err := saveBofaCC2023(); err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			os.Exit(1)
 		}
-		if !d.IsDir() {
-			// fmt.Println(path)
-			files = append(files, path)
+		return
+	}
 
-		}
-		return nil
-	})
+	var err error
+	switch os.Args[1] {
+	case "import":
+		err = runImport(os.Args[2:])
+	case "history":
+		err = runHistory(os.Args[2:])
+	case "check":
+		err = runCheck(os.Args[2:])
+	case "dedup":
+		err = runDedup(os.Args[2:])
+	case "categorize":
+		err = runCategorize(os.Args[2:])
+	case "train":
+		err = runTrain(os.Args[2:])
+	case "suggest":
+		err = runSuggest(os.Args[2:])
+	case "recurring":
+		err = runRecurring(os.Args[2:])
+	case "report":
+		err = runReport(os.Args[2:])
+	case "cashflow":
+		err = runCashFlow(os.Args[2:])
+	case "help", "-h", "-help", "--help":
+		fmt.Print(usage)
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
 
 	if err != nil {
-		fmt.Println("Error:", err)
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
+	}
+}
+
+// saveBofaCC2023 saves the transactions of the bofa credit card statements of 2023 to a single csv
+func saveBofaCC2023() error {
+	files, err := listFiles("./temp/bofa-cc/2023")
+	if err != nil {
+		return err
 	}
 
 	statements := make([]bofacc.Statement, 0, len(files))
 
 	for _, file := range files {
-		// if file != "temp/bofa-cc/2023/plain_bofa-cc-1810-2023-08-11"{
-		// 	continue
-		// }
 		fmt.Printf("Processing file %s #########################\n", file)
 		data, err := os.ReadFile(file)
 		if err != nil {
-			fmt.Println(err)
-			return
+			return err
 		}
 		s, err := bofacc.ParseStatement(string(data))
 		if err != nil {
-			fmt.Println(err)
-			return
+			return err
 		}
 
 		statements = append(statements, *s)
-		// if err := bofacc.SaveTransactionsToCSV(*s); err != nil {
-		// 	fmt.Println(err)
-		// 	return
-		// }
 	}
 
-	if err := bofacc.SaveStatementsToCSV(statements, "temp/bofa-cc-2023.csv"); err != nil {
-		fmt.Println(err)
-		return
-	}
+	return bofacc.SaveStatementsToCSV(statements, "temp/bofa-cc-2023.csv")
+}
 
+// listFiles returns the files under the paths, walking the directories
+func listFiles(paths ...string) ([]string, error) {
+	files := []string{}
+	for _, path := range paths {
+		err := filepath.WalkDir(path, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !d.IsDir() {
+				files = append(files, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return files, err
+		}
+	}
+	return files, nil
 }
@@ -0,0 +1,28 @@
+package merchant
+
+import (
+	"fmt"
+)
+
+func ExampleNormalizer_Normalize() {
+	n, err := New([]Alias{{Merchant: "Home Depot", Patterns: []string{"HOME ?DEPOT"}}})
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	for _, description := range []string{
+		"THE HOME DEPOT #3644 SAN JOSE CA",
+		"HOMEDEPOT.COM 111-111-1111 BC",
+		"COSTCO WHSE #1206 SAN JOSE CA",
+		"TST*WATERPARK - KIOSK 1 SAN JOSE CA",
+	} {
+		fmt.Printf("%+v\n", n.Normalize(description))
+	}
+
+	// Output:
+	// {Merchant:Home Depot StoreID:3644 City:SAN JOSE State:CA}
+	// {Merchant:Home Depot StoreID: City: State:BC}
+	// {Merchant:COSTCO WHSE StoreID:1206 City:SAN JOSE State:CA}
+	// {Merchant:WATERPARK StoreID:1 City:SAN JOSE State:CA}
+}
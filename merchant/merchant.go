@@ -0,0 +1,182 @@
+// package merchant provides the normalizer of the transaction descriptions into the merchant, the store number and
+// the location, so the transactions can be grouped by the merchant
+package merchant
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/muly/bank-tx/util"
+)
+
+// prefixes are the payment processor prefixes of the merchant names, e.g. "TST*" of toast and "SQ *" of square
+var prefixes = []string{"TST* ", "TST*", "SQ * ", "SQ *", "SQ*", "SP * ", "SP *", "SP*", "SP ", "PAYPAL *", "PY *", "DD *"}
+
+var (
+	reStoreNumber = regexp.MustCompile(`^#?\d+$`)
+	rePhone       = regexp.MustCompile(`^(\d{3}-\d{3}-\d{4}|\(\d{3}\)\d{3}-\d{4}|\d{10})$`)
+	reMasked      = regexp.MustCompile(`^[A-Z]{0,4}[*X]{2,}\d+$`)
+)
+
+// states are the codes of the us states and territories and of the canadian provinces
+var states = map[string]bool{}
+
+func init() {
+	for _, code := range strings.Fields(`AL AK AZ AR CA CO CT DE DC FL GA HI ID IL IN IA KS KY LA ME MD MA MI MN MS MO MT NE NV NH NJ
+		NM NY NC ND OH OK OR PA RI SC SD TN TX UT VT VA WA WV WI WY PR VI GU AB BC MB NB NL NS NT NU ON PE QC SK YT`) {
+		states[code] = true
+	}
+}
+
+// Info is the merchant of a transaction description, the fields not in the description are empty
+type Info struct {
+	Merchant string
+	StoreID  string
+	City     string
+	State    string
+}
+
+// Parse splits the description into the merchant, the store number and the location. The processor prefixes,
+// the phone numbers and the masked references are dropped, the text after " - " is the location within the merchant,
+// e.g. "TST*WATERPARK - KIOSK 1 SAN JOSE CA" is the merchant "WATERPARK" with the store "1" in "SAN JOSE", "CA".
+// The city is only known after a store number or a phone number, it can not be told apart from the merchant otherwise.
+func Parse(description string) Info {
+	s := strings.ToUpper(strings.Join(strings.Fields(description), " "))
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(s, prefix) {
+			s = strings.TrimSpace(s[len(prefix):])
+			break
+		}
+	}
+
+	tokens := strings.Fields(s)
+	var info Info
+	end := len(tokens) // end of the merchant name
+	cut := len(tokens) // start of the location
+	for i, token := range tokens {
+		if i == 0 {
+			if star := strings.Index(token, "*"); star > 0 {
+				// a reference after the merchant, e.g. "AMAZON.COM*RT4XX1"
+				tokens[0] = token[:star]
+				end, cut = 1, 1
+				break
+			}
+			continue
+		}
+		if token == "-" && end == len(tokens) {
+			end = i
+			continue
+		}
+		if reStoreNumber.MatchString(token) || rePhone.MatchString(token) || reMasked.MatchString(token) {
+			if i < end {
+				end = i
+			}
+			cut = i
+			break
+		}
+		if star := strings.Index(token, "*"); star > 0 && i < end {
+			tokens[i] = token[:star]
+			end, cut = i+1, i+1
+			break
+		}
+	}
+
+	var location []string
+	if cut < len(tokens) {
+		for _, token := range tokens[cut:] {
+			switch {
+			case rePhone.MatchString(token), reMasked.MatchString(token):
+			case reStoreNumber.MatchString(token) && info.StoreID == "":
+				info.StoreID = strings.TrimLeft(token, "#")
+			default:
+				location = append(location, token)
+			}
+		}
+		if n := len(location); n > 0 && states[location[n-1]] {
+			info.State = location[n-1]
+			location = location[:n-1]
+		}
+		info.City = strings.Join(location, " ")
+	} else if end == len(tokens) && end > 1 && states[tokens[end-1]] {
+		info.State = tokens[end-1]
+		end--
+	}
+
+	info.Merchant = strings.Trim(strings.Join(tokens[:end], " "), " ,.-*#")
+	return info
+}
+
+// Alias maps the variants of a merchant to its canonical name
+type Alias struct {
+	Merchant string `yaml:"merchant" json:"merchant"`
+	// Patterns are the case insensitive regexps of the descriptions of the merchant
+	Patterns []string `yaml:"patterns" json:"patterns"`
+}
+
+// File is the alias file
+type File struct {
+	Aliases []Alias `yaml:"aliases" json:"aliases"`
+}
+
+type compiledAlias struct {
+	merchant string
+	patterns []*regexp.Regexp
+}
+
+// Normalizer parses the descriptions and maps the merchants to their canonical names by the aliases
+type Normalizer struct {
+	aliases []compiledAlias
+}
+
+// Load loads the alias file, the ".json" files are read as json and the others as yaml
+func Load(filename string) (*Normalizer, error) {
+	var f File
+	if err := util.DecodeFile(filename, &f); err != nil {
+		return nil, err
+	}
+
+	n, err := New(f.Aliases)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", filename, err)
+	}
+	return n, nil
+}
+
+// New validates the aliases and returns the normalizer, the first alias matching a description is used
+func New(aliases []Alias) (*Normalizer, error) {
+	n := &Normalizer{aliases: make([]compiledAlias, 0, len(aliases))}
+	for i, a := range aliases {
+		if a.Merchant == "" {
+			return nil, fmt.Errorf("alias %d: missing merchant", i+1)
+		}
+		c := compiledAlias{merchant: a.Merchant}
+		for _, pattern := range a.Patterns {
+			re, err := regexp.Compile("(?i)" + pattern)
+			if err != nil {
+				return nil, fmt.Errorf("%s: invalid pattern: %v", a.Merchant, err)
+			}
+			c.patterns = append(c.patterns, re)
+		}
+		n.aliases = append(n.aliases, c)
+	}
+	return n, nil
+}
+
+// Normalize parses the description and replaces the merchant with its canonical name when an alias matches
+// the description. A nil normalizer has no aliases.
+func (n *Normalizer) Normalize(description string) Info {
+	info := Parse(description)
+	if n == nil {
+		return info
+	}
+	for _, a := range n.aliases {
+		for _, re := range a.patterns {
+			if re.MatchString(description) {
+				info.Merchant = a.merchant
+				return info
+			}
+		}
+	}
+	return info
+}
@@ -0,0 +1,70 @@
+package merchant
+
+import (
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		description string
+		expected    Info
+	}{
+		{"COSTCO WHSE #1206 SAN JOSE CA", Info{Merchant: "COSTCO WHSE", StoreID: "1206", City: "SAN JOSE", State: "CA"}},
+		{"THE HOME DEPOT #3644 TOWN STATE", Info{Merchant: "THE HOME DEPOT", StoreID: "3644", City: "TOWN STATE"}},
+		{"TST*WATERPARK - KIOSK 1 SAN JOSE CA", Info{Merchant: "WATERPARK", StoreID: "1", City: "SAN JOSE", State: "CA"}},
+		{"HOMEDEPOT.COM 111-111-1111 BC", Info{Merchant: "HOMEDEPOT.COM", State: "BC"}},
+		{"SQ *BLUE BOTTLE COFFEE Oakland CA", Info{Merchant: "BLUE BOTTLE COFFEE OAKLAND", State: "CA"}},
+		{"SP  ACME SOCKS 8005551234 NY", Info{Merchant: "ACME SOCKS", State: "NY"}},
+		{"SHELL OIL 57444 SAN JOSE CA", Info{Merchant: "SHELL OIL", StoreID: "57444", City: "SAN JOSE", State: "CA"}},
+		{"AMAZON.COM*RT4XX1 AMZN.COM/BILL WA", Info{Merchant: "AMAZON.COM", City: "AMZN.COM/BILL", State: "WA"}},
+		{"AMZN MKTP US*2K1AB2CD3 SEATTLE WA", Info{Merchant: "AMZN MKTP US", City: "SEATTLE", State: "WA"}},
+		{"EEERERERERE MTG PAYMENTS ****311244", Info{Merchant: "EEERERERERE MTG PAYMENTS"}},
+		{"TD BILL PAY SERV, BANK OF AMERICA ONLINE PMT TDB****34454454", Info{Merchant: "TD BILL PAY SERV, BANK OF AMERICA ONLINE PMT"}},
+		{"PAYMENT - THANK YOU", Info{Merchant: "PAYMENT"}},
+		{"NETFLIX.COM", Info{Merchant: "NETFLIX.COM"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			actual := Parse(tt.description)
+			if actual != tt.expected {
+				t.Errorf("expected %+v, got %+v", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	n, err := Load("aliases.example.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		description string
+		expected    string
+	}{
+		{"COSTCO WHSE #1111 TOWN STATE", "Costco"},
+		{"COSTCO GAS #1111 TOWN STATE", "Costco Gas"},
+		{"THE HOME DEPOT #3644 TOWN STATE", "Home Depot"},
+		{"HOMEDEPOT.COM 111-111-1111 BC", "Home Depot"},
+		{"TST*WATERPARK - KIOSK 1 TOWN STATE", "WATERPARK"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			if actual := n.Normalize(tt.description).Merchant; actual != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, actual)
+			}
+		})
+	}
+
+	var none *Normalizer
+	if actual := none.Normalize("COSTCO WHSE #1111").Merchant; actual != "COSTCO WHSE" {
+		t.Errorf("expected %q without aliases, got %q", "COSTCO WHSE", actual)
+	}
+
+	if _, err := New([]Alias{{Merchant: "Broken", Patterns: []string{"("}}}); err == nil {
+		t.Error("expected an error for an invalid pattern")
+	}
+}
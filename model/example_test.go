@@ -0,0 +1,50 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+func ExampleStatement_AssignIDs() {
+	date := time.Date(2023, 4, 3, 0, 0, 0, 0, time.UTC)
+	s := Statement{
+		AccountNumber: "123-4567890",
+		Transactions: []Transaction{
+			{PostingDate: date, Description: "ACH DEPOSIT, rere erer ererereL", Amount: 6377.30},
+			{PostingDate: date, Description: "ACH  DEPOSIT, RERE erer ererereL", Amount: 6377.30},
+			{PostingDate: date, Description: "TD BILL PAY SERV", Amount: -500},
+		},
+	}
+
+	s.AssignIDs()
+	for _, tx := range s.Transactions {
+		fmt.Println(tx.ID, tx.Description)
+	}
+
+	// Output:
+	// 1c385de7a1f53eed95c3 ACH DEPOSIT, rere erer ererereL
+	// 8fc6e715468319849d6d ACH  DEPOSIT, RERE erer ererereL
+	// 29b6b6d257fb6ae3239f TD BILL PAY SERV
+}
+
+func ExampleStatement_Flow() {
+	checking := Statement{AccountType: Checking}
+	card := Statement{AccountType: CreditCard}
+	for _, c := range []struct {
+		s  Statement
+		tx Transaction
+	}{
+		{checking, Transaction{Description: "ACH DEPOSIT", Amount: 6377.30}},
+		{checking, Transaction{Description: "TD BILL PAY SERV", Amount: -500}},
+		{card, Transaction{Description: "COSTCO WHSE #1111", Amount: 120.50}},
+		{card, Transaction{Description: "PAYMENT - THANK YOU", Amount: -500}},
+	} {
+		fmt.Printf("%s %s %.2f %s\n", c.s.AccountType, c.tx.Description, c.s.Flow(c.tx), c.s.Direction(c.tx))
+	}
+
+	// Output:
+	// checking ACH DEPOSIT 6377.30 inflow
+	// checking TD BILL PAY SERV -500.00 outflow
+	// credit_card COSTCO WHSE #1111 -120.50 outflow
+	// credit_card PAYMENT - THANK YOU 500.00 inflow
+}
@@ -0,0 +1,52 @@
+package model
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// NormalizeDescription uppercases the description and collapses its spaces, so the descriptions that differ
+// only by the layout of the source compare equal
+func NormalizeDescription(description string) string {
+	return strings.Join(strings.Fields(strings.ToUpper(description)), " ")
+}
+
+// TransactionID returns the deterministic id of the transaction of the account. It is derived from the account number,
+// the transaction and posting dates, the amount, the normalized description and the occurrence, the index of the transaction
+// among the ones of the statement having all the other fields identical, e.g. the two same day deposits of the same amount.
+func TransactionID(accountNumber string, tx Transaction, occurrence int) string {
+	sum := sha256.Sum256([]byte(strings.Join([]string{
+		accountNumber,
+		formatDate(tx.TransactionDate),
+		formatDate(tx.PostingDate),
+		fmt.Sprintf("%.2f", tx.Amount),
+		NormalizeDescription(tx.Description),
+		fmt.Sprint(occurrence),
+	}, "|")))
+	return hex.EncodeToString(sum[:])[:20]
+}
+
+// AssignIDs sets the ID of the transactions that have none, the IDs are stable as long as the parser
+// reads the statement the same way, so the re-imports of the statement get the same IDs
+func (s *Statement) AssignIDs() {
+	occurrences := map[string]int{}
+	for i := range s.Transactions {
+		tx := &s.Transactions[i]
+		key := TransactionID(s.AccountNumber, *tx, 0)
+		occurrence := occurrences[key]
+		occurrences[key]++
+		if tx.ID == "" {
+			tx.ID = TransactionID(s.AccountNumber, *tx, occurrence)
+		}
+	}
+}
+
+func formatDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format("2006-01-02")
+}
@@ -0,0 +1,146 @@
+// package model provides the bank neutral statement and transaction types that the importers and exporters share
+package model
+
+import (
+	"time"
+
+	"github.com/muly/bank-tx/util"
+)
+
+// Account types
+const (
+	Checking   = "checking"
+	CreditCard = "credit_card"
+	Brokerage  = "brokerage"
+)
+
+// Directions of the money
+const (
+	Outflow = "outflow"
+	Inflow  = "inflow"
+)
+
+// Sources of the spending category
+const (
+	// SourceManual is the category set by the user
+	SourceManual = "manual"
+	// SourceRules is the category set by the rules, see package categorize
+	SourceRules = "rules"
+	// SourceModel is the category suggested by the classifier, see package classify
+	SourceModel = "model"
+)
+
+// Institutions
+const (
+	TD         = "td"
+	BofA       = "bofa"
+	Chase      = "chase"
+	Amex       = "amex"
+	CapitalOne = "capital_one"
+	Discover   = "discover"
+	Citi       = "citi"
+	WellsFargo = "wells_fargo"
+)
+
+// Transaction struct to hold transaction data.
+// Amount is signed the way it moves the statement balance: for checking accounts deposits are positive and
+// payments are negative, for credit cards purchases are positive and payments are negative.
+// ID is the stable key of the transaction, see TransactionID, it is empty until the IDs are assigned.
+// TransferID is the ID of the other side of a transfer between the accounts, e.g. the credit card payment from checking,
+// the transfers are neither spending nor income.
+// SpendingCategory is what the money was spent on, hierarchical with ":" separating the levels, e.g. "Food:Groceries",
+// while Category is the section of the statement the transaction is in. CategorySource tells who set the spending category,
+// e.g. SourceManual, it is empty for the categories set before the sources were recorded.
+// TxType and Counterparty are the kind of the payment, e.g. "ACH" or "Zelle", and the other party of it, for the banks
+// printing them in the description; the reference printed with them is the ReferenceNumber.
+type Transaction struct {
+	ID               string
+	TransactionDate  time.Time
+	PostingDate      time.Time
+	Description      string
+	ReferenceNumber  string
+	AccountNumber    string
+	Amount           float64
+	Category         string
+	TransferID       string
+	SpendingCategory string
+	CategorySource   string
+	TxType           string
+	Counterparty     string
+}
+
+// Statement struct to hold overall statement info
+type Statement struct {
+	Institution      string
+	AccountType      string
+	AccountNumber    string
+	PeriodStartDate  time.Time
+	PeriodEndDate    time.Time
+	BeginningBalance float64
+	EndingBalance    float64
+	Transactions     []Transaction
+}
+
+// Total returns the sum of the transaction amounts
+func (s Statement) Total() float64 {
+	total := 0.0
+	for _, tx := range s.Transactions {
+		total += tx.Amount
+	}
+	return total
+}
+
+// Flow returns the cash flow of the transaction, negative when the money leaves the account: the credit card
+// purchases move the balance up but they are outflows, and the card payments bring money into the card account
+func (s Statement) Flow(tx Transaction) float64 {
+	if s.AccountType == CreditCard {
+		return -tx.Amount
+	}
+	return tx.Amount
+}
+
+// Direction returns Outflow or Inflow of the transaction by its Flow
+func (s Statement) Direction(tx Transaction) string {
+	if s.Flow(tx) < 0 {
+		return Outflow
+	}
+	return Inflow
+}
+
+// Check is the result of one validation of the statement
+type Check struct {
+	Name     string
+	Passed   bool
+	Expected float64
+	Actual   float64
+}
+
+// Validation holds the checks of the statement, Valid is set when all of them pass
+type Validation struct {
+	Valid  bool
+	Checks []Check
+}
+
+// Validate checks that the transactions add up to the ending balance ("balance") and that they are posted within
+// the statement period ("period", the number of transactions posted outside of it)
+func (s Statement) Validate() Validation {
+	outside := 0
+	for _, tx := range s.Transactions {
+		if tx.PostingDate.Before(s.PeriodStartDate) || tx.PostingDate.After(s.PeriodEndDate) {
+			outside++
+		}
+	}
+
+	v := Validation{
+		Valid: true,
+		Checks: []Check{
+			{Name: "balance", Expected: util.RoundToTwoDecimal(s.EndingBalance), Actual: util.RoundToTwoDecimal(s.BeginningBalance + s.Total())},
+			{Name: "period", Expected: 0, Actual: float64(outside)},
+		},
+	}
+	for i := range v.Checks {
+		v.Checks[i].Passed = v.Checks[i].Expected == v.Checks[i].Actual
+		v.Valid = v.Valid && v.Checks[i].Passed
+	}
+	return v
+}
@@ -0,0 +1,167 @@
+package ofx
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/muly/bank-tx/model"
+)
+
+func ExampleReadStatements() {
+	data := `OFXHEADER:100
+DATA:OFXSGML
+VERSION:102
+SECURITY:NONE
+ENCODING:USASCII
+CHARSET:1252
+COMPRESSION:NONE
+OLDFILEUID:NONE
+NEWFILEUID:NONE
+
+<OFX>
+<SIGNONMSGSRSV1>
+<SONRS>
+<STATUS><CODE>0<SEVERITY>INFO</STATUS>
+<DTSERVER>20230421120000.000[-5:EST]
+<LANGUAGE>ENG
+<FI><ORG>TD<FID>1001</FI>
+</SONRS>
+</SIGNONMSGSRSV1>
+<BANKMSGSRSV1>
+<STMTTRNRS>
+<TRNUID>0
+<STATUS><CODE>0<SEVERITY>INFO</STATUS>
+<STMTRS>
+<CURDEF>USD
+<BANKACCTFROM>
+<BANKID>011103093
+<ACCTID>1234567890
+<ACCTTYPE>CHECKING
+</BANKACCTFROM>
+<BANKTRANLIST>
+<DTSTART>20230321
+<DTEND>20230420
+<STMTTRN>
+<TRNTYPE>CREDIT
+<DTPOSTED>20230402120000.000
+<TRNAMT>6377.30
+<FITID>20230402-1
+<NAME>ACH DEPOSIT
+<MEMO>rere erer ererereL
+</STMTTRN>
+<STMTTRN>
+<TRNTYPE>DEBIT
+<DTPOSTED>20230411120000.000
+<TRNAMT>-500.00
+<FITID>20230411-1
+<NAME>TD BILL PAY SERV
+<MEMO>BANK OF AMERICA ONLINE PMT
+</STMTTRN>
+</BANKTRANLIST>
+<LEDGERBAL>
+<BALAMT>10131.07
+<DTASOF>20230420
+</LEDGERBAL>
+</STMTRS>
+</STMTTRNRS>
+</BANKMSGSRSV1>
+</OFX>
+`
+
+	statements, err := ReadStatements(strings.NewReader(data))
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	for _, s := range statements {
+		fmt.Println(s.Institution, s.AccountType, s.AccountNumber, s.PeriodStartDate.Format("2006-01-02"), s.PeriodEndDate.Format("2006-01-02"), s.BeginningBalance, s.EndingBalance)
+		for _, tx := range s.Transactions {
+			fmt.Printf("%+v\n", tx)
+		}
+	}
+
+	// Output:
+	// td checking 1234567890 2023-03-21 2023-04-20 4253.77 10131.07
+	// {ID: TransactionDate:2023-04-02 00:00:00 +0000 UTC PostingDate:2023-04-02 00:00:00 +0000 UTC Description:ACH DEPOSIT rere erer ererereL ReferenceNumber:20230402-1 AccountNumber: Amount:6377.3 Category:CREDIT TransferID: SpendingCategory: CategorySource: TxType: Counterparty:}
+	// {ID: TransactionDate:2023-04-11 00:00:00 +0000 UTC PostingDate:2023-04-11 00:00:00 +0000 UTC Description:TD BILL PAY SERV BANK OF AMERICA ONLINE PMT ReferenceNumber:20230411-1 AccountNumber: Amount:-500 Category:DEBIT TransferID: SpendingCategory: CategorySource: TxType: Counterparty:}
+}
+
+func ExampleWriteStatement() {
+	s := model.Statement{
+		Institution:      model.BofA,
+		AccountType:      model.CreditCard,
+		AccountNumber:    "4400123456781234",
+		PeriodStartDate:  time.Date(2024, 9, 12, 0, 0, 0, 0, time.UTC),
+		PeriodEndDate:    time.Date(2024, 10, 11, 0, 0, 0, 0, time.UTC),
+		BeginningBalance: 1905.57,
+		EndingBalance:    42.75,
+		Transactions: []model.Transaction{
+			{
+				TransactionDate: time.Date(2024, 9, 13, 0, 0, 0, 0, time.UTC),
+				PostingDate:     time.Date(2024, 9, 16, 0, 0, 0, 0, time.UTC),
+				Description:     "ERERE RERE COUNTY SCHOOL FDFDDF-DDFDFD DF",
+				ReferenceNumber: "0881",
+				Amount:          42.75,
+			},
+			{
+				TransactionDate: time.Date(2024, 9, 28, 0, 0, 0, 0, time.UTC),
+				PostingDate:     time.Date(2024, 9, 30, 0, 0, 0, 0, time.UTC),
+				Description:     "PAYMENT - THANK YOU",
+				ReferenceNumber: "0027",
+				Amount:          -1905.57,
+			},
+		},
+	}
+
+	if err := WriteStatement(os.Stdout, s); err != nil {
+		fmt.Println(err)
+	}
+
+	// Output:
+	// <?xml version="1.0" encoding="UTF-8" standalone="no"?>
+	// <?OFX OFXHEADER="200" VERSION="220" SECURITY="NONE" OLDFILEUID="NONE" NEWFILEUID="NONE"?>
+	// <OFX>
+	//   <SIGNONMSGSRSV1>
+	//     <SONRS>
+	//       <STATUS><CODE>0</CODE><SEVERITY>INFO</SEVERITY></STATUS>
+	//       <DTSERVER>20241011</DTSERVER>
+	//       <LANGUAGE>ENG</LANGUAGE>
+	//       <FI><ORG>BOFA</ORG></FI>
+	//     </SONRS>
+	//   </SIGNONMSGSRSV1>
+	//   <CREDITCARDMSGSRSV1>
+	//     <CCSTMTTRNRS>
+	//       <TRNUID>0</TRNUID>
+	//       <STATUS><CODE>0</CODE><SEVERITY>INFO</SEVERITY></STATUS>
+	//       <CCSTMTRS>
+	//         <CURDEF>USD</CURDEF>
+	//         <CCACCTFROM><ACCTID>4400123456781234</ACCTID></CCACCTFROM>
+	//         <BANKTRANLIST>
+	//           <DTSTART>20240912</DTSTART>
+	//           <DTEND>20241011</DTEND>
+	//           <STMTTRN>
+	//             <TRNTYPE>DEBIT</TRNTYPE>
+	//             <DTPOSTED>20240916</DTPOSTED>
+	//             <DTUSER>20240913</DTUSER>
+	//             <TRNAMT>-42.75</TRNAMT>
+	//             <FITID>7a680ff62307b7979eaa</FITID>
+	//             <NAME>ERERE RERE COUNTY SCHOOL FDFDDF-</NAME>
+	//             <MEMO>ERERE RERE COUNTY SCHOOL FDFDDF-DDFDFD DF</MEMO>
+	//           </STMTTRN>
+	//           <STMTTRN>
+	//             <TRNTYPE>CREDIT</TRNTYPE>
+	//             <DTPOSTED>20240930</DTPOSTED>
+	//             <DTUSER>20240928</DTUSER>
+	//             <TRNAMT>1905.57</TRNAMT>
+	//             <FITID>27075a33e1063977c695</FITID>
+	//             <NAME>PAYMENT - THANK YOU</NAME>
+	//           </STMTTRN>
+	//         </BANKTRANLIST>
+	//         <LEDGERBAL><BALAMT>-42.75</BALAMT><DTASOF>20241011</DTASOF></LEDGERBAL>
+	//       </CCSTMTRS>
+	//     </CCSTMTTRNRS>
+	//   </CREDITCARDMSGSRSV1>
+	// </OFX>
+}
@@ -0,0 +1,179 @@
+// package ofx provides the functions to read the ofx/qfx (quicken web connect) files into the bank neutral model.
+// Both the ofx 1.x (sgml) and the ofx 2.x (xml) formats are supported.
+package ofx
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/muly/bank-tx/model"
+	"github.com/muly/bank-tx/util"
+)
+
+// statement aggregates by the message set they are in
+var statementAggregates = []struct {
+	name        string
+	accountFrom string
+	tranList    string
+	accountType string
+}{
+	{name: "STMTRS", accountFrom: "BANKACCTFROM", tranList: "BANKTRANLIST", accountType: model.Checking},
+	{name: "CCSTMTRS", accountFrom: "CCACCTFROM", tranList: "BANKTRANLIST", accountType: model.CreditCard},
+	{name: "INVSTMTRS", accountFrom: "INVACCTFROM", tranList: "INVTRANLIST", accountType: model.Brokerage},
+}
+
+// institutions maps the FI/ORG names the banks use, lowercased with the punctuation as spaces, to the model institutions
+var institutions = []struct {
+	pattern     *regexp.Regexp
+	institution string
+}{
+	{regexp.MustCompile(`^td( bank)?\b`), model.TD},
+	{regexp.MustCompile(`^(bofa|boa|bank of america)\b`), model.BofA},
+	{regexp.MustCompile(`^(jpmorgan )?chase\b|^jpmc\b`), model.Chase},
+	{regexp.MustCompile(`^(amex|american express)\b`), model.Amex},
+	{regexp.MustCompile(`^capital ?one\b`), model.CapitalOne},
+	{regexp.MustCompile(`^discover\b`), model.Discover},
+	{regexp.MustCompile(`^citi(bank|cards|group)?\b`), model.Citi},
+	{regexp.MustCompile(`^(wells fargo|wf|wfb)\b`), model.WellsFargo},
+}
+
+var reNotAlphanumeric = regexp.MustCompile(`[^a-z0-9]+`)
+
+// institution returns the model institution of the FI/ORG name, e.g. model.TD for "TD Bank, N.A.",
+// or the lowercased name for the other institutions
+func institution(org string) string {
+	name := strings.TrimSpace(reNotAlphanumeric.ReplaceAllString(strings.ToLower(org), " "))
+	for _, i := range institutions {
+		if i.pattern.MatchString(name) {
+			return i.institution
+		}
+	}
+	return strings.ToLower(strings.TrimSpace(org))
+}
+
+// ReadStatements reads all the statements of the ofx file.
+// STMTTRN is mapped to Transaction, LEDGERBAL to EndingBalance and DTSTART/DTEND to the statement period.
+// The credit card amounts are negated, as ofx has the charges as negative. FI/ORG is mapped to the model institution,
+// so the accounts read from ofx are the same as those read from the statements.
+func ReadStatements(r io.Reader) ([]model.Statement, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	root, err := parseDocument(string(data))
+	if err != nil {
+		return nil, err
+	}
+
+	fi := institution(root.text("SIGNONMSGSRSV1", "SONRS", "FI", "ORG"))
+
+	var statements []model.Statement
+	for _, aggregate := range statementAggregates {
+		for _, rs := range root.findAll(aggregate.name) {
+			statement, err := readStatement(rs, aggregate.accountFrom, aggregate.tranList, aggregate.accountType)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %v", aggregate.name, err)
+			}
+			statement.Institution = fi
+			statements = append(statements, *statement)
+		}
+	}
+
+	if len(statements) == 0 {
+		return nil, fmt.Errorf("no statements in ofx")
+	}
+	return statements, nil
+}
+
+func readStatement(rs *node, accountFrom, tranList, accountType string) (*model.Statement, error) {
+	var err error
+	statement := model.Statement{
+		AccountType:   accountType,
+		AccountNumber: rs.text(accountFrom, "ACCTID"),
+	}
+
+	// credit card balances and amounts are from the point of view of the card holder
+	sign := 1.0
+	if accountType == model.CreditCard {
+		sign = -1.0
+	}
+
+	list := rs.child(tranList)
+	if list == nil {
+		return nil, fmt.Errorf("missing %s", tranList)
+	}
+	if statement.PeriodStartDate, err = parseDate(list.text("DTSTART")); err != nil {
+		return nil, fmt.Errorf("DTSTART: %v", err)
+	}
+	if statement.PeriodEndDate, err = parseDate(list.text("DTEND")); err != nil {
+		return nil, fmt.Errorf("DTEND: %v", err)
+	}
+
+	for _, trn := range list.findAll("STMTTRN") {
+		tx, err := readTransaction(trn)
+		if err != nil {
+			return nil, fmt.Errorf("STMTTRN %s: %v", trn.text("FITID"), err)
+		}
+		tx.Amount *= sign
+		statement.Transactions = append(statement.Transactions, *tx)
+	}
+
+	balance := rs.text("LEDGERBAL", "BALAMT")
+	if accountType == model.Brokerage {
+		balance = rs.text("INVBAL", "AVAILCASH")
+	}
+	if balance != "" {
+		statement.EndingBalance, err = strconv.ParseFloat(balance, 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse balance: %s, error: %v", balance, err)
+		}
+		statement.EndingBalance *= sign
+	}
+	statement.BeginningBalance = util.RoundToTwoDecimal(statement.EndingBalance - statement.Total())
+
+	return &statement, nil
+}
+
+func readTransaction(trn *node) (*model.Transaction, error) {
+	var err error
+	tx := model.Transaction{
+		ReferenceNumber: trn.text("FITID"),
+		Category:        trn.text("TRNTYPE"),
+		Description:     strings.TrimSpace(trn.text("NAME")),
+	}
+	if memo := strings.TrimSpace(trn.text("MEMO")); strings.HasPrefix(memo, tx.Description) {
+		tx.Description = memo // NAME is the memo truncated to the ofx length limit
+	} else if memo != "" {
+		tx.Description = strings.TrimSpace(tx.Description + " " + memo)
+	}
+
+	if tx.PostingDate, err = parseDate(trn.text("DTPOSTED")); err != nil {
+		return nil, fmt.Errorf("DTPOSTED: %v", err)
+	}
+	tx.TransactionDate = tx.PostingDate
+	if dtUser := trn.text("DTUSER"); dtUser != "" {
+		if tx.TransactionDate, err = parseDate(dtUser); err != nil {
+			return nil, fmt.Errorf("DTUSER: %v", err)
+		}
+	}
+
+	amount := trn.text("TRNAMT")
+	if tx.Amount, err = strconv.ParseFloat(strings.ReplaceAll(amount, ",", "."), 64); err != nil {
+		return nil, fmt.Errorf("failed to parse amount: %s, error: %v", amount, err)
+	}
+
+	return &tx, nil
+}
+
+// parseDate parses the date part of the ofx datetime like "20240112", "20240112120000" and "20240112120000.000[-5:EST]"
+func parseDate(s string) (time.Time, error) {
+	if len(s) < 8 {
+		return time.Time{}, fmt.Errorf("invalid date %q", s)
+	}
+	return time.Parse("20060102", s[:8])
+}
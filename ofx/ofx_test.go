@@ -0,0 +1,156 @@
+package ofx
+
+import (
+	"bytes"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/muly/bank-tx/model"
+	"github.com/muly/bank-tx/td"
+)
+
+func TestReadStatements_creditCardXML(t *testing.T) {
+	data := `<?xml version="1.0" encoding="UTF-8" standalone="no"?>
+<?OFX OFXHEADER="200" VERSION="220" SECURITY="NONE" OLDFILEUID="NONE" NEWFILEUID="NONE"?>
+<OFX>
+  <CREDITCARDMSGSRSV1>
+    <CCSTMTTRNRS>
+      <TRNUID>0</TRNUID>
+      <CCSTMTRS>
+        <CURDEF>USD</CURDEF>
+        <CCACCTFROM><ACCTID>4400123456781234</ACCTID></CCACCTFROM>
+        <BANKTRANLIST>
+          <DTSTART>20240912</DTSTART>
+          <DTEND>20241011</DTEND>
+          <STMTTRN>
+            <TRNTYPE>DEBIT</TRNTYPE>
+            <DTPOSTED>20240916</DTPOSTED>
+            <DTUSER>20240913</DTUSER>
+            <TRNAMT>-42.75</TRNAMT>
+            <FITID>0881</FITID>
+            <NAME>B&amp;B PRODUCE</NAME>
+          </STMTTRN>
+          <STMTTRN>
+            <TRNTYPE>CREDIT</TRNTYPE>
+            <DTPOSTED>20240930</DTPOSTED>
+            <TRNAMT>1905.57</TRNAMT>
+            <FITID>0027</FITID>
+            <NAME>PAYMENT - THANK YOU</NAME>
+          </STMTTRN>
+        </BANKTRANLIST>
+        <LEDGERBAL><BALAMT>-1049.90</BALAMT><DTASOF>20241011</DTASOF></LEDGERBAL>
+      </CCSTMTRS>
+    </CCSTMTTRNRS>
+  </CREDITCARDMSGSRSV1>
+</OFX>`
+
+	statements, err := ReadStatements(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("ReadStatements() error = %v", err)
+	}
+	if len(statements) != 1 {
+		t.Fatalf("ReadStatements() got %d statements, want 1", len(statements))
+	}
+
+	s := statements[0]
+	if s.AccountType != model.CreditCard || s.AccountNumber != "4400123456781234" {
+		t.Errorf("ReadStatements() account = %s %s", s.AccountType, s.AccountNumber)
+	}
+	if s.EndingBalance != 1049.90 || s.BeginningBalance != 2912.72 {
+		t.Errorf("ReadStatements() balances = %v %v, want 2912.72 1049.90", s.BeginningBalance, s.EndingBalance)
+	}
+	if got := s.Transactions[0]; got.Amount != 42.75 || got.Description != "B&B PRODUCE" || got.TransactionDate.Day() != 13 {
+		t.Errorf("ReadStatements() purchase = %+v", got)
+	}
+	if got := s.Transactions[1]; got.Amount != -1905.57 || got.ReferenceNumber != "0027" {
+		t.Errorf("ReadStatements() payment = %+v", got)
+	}
+}
+
+func TestInstitution(t *testing.T) {
+	tests := []struct {
+		org  string
+		want string
+	}{
+		{"TD", model.TD},
+		{"TD Bank, N.A.", model.TD},
+		{"Bank of America", model.BofA},
+		{"BOFA", model.BofA},
+		{"JPMorgan Chase Bank, N.A.", model.Chase},
+		{"AMEX", model.Amex},
+		{"American Express", model.Amex},
+		{"CAPITAL_ONE", model.CapitalOne},
+		{"Capital One", model.CapitalOne},
+		{"Discover Financial Services", model.Discover},
+		{"Citibank", model.Citi},
+		{"WELLS_FARGO", model.WellsFargo},
+		{"Wells Fargo Bank", model.WellsFargo},
+		{"Ally Bank", "ally bank"},
+		{"Tdecu", "tdecu"},
+	}
+
+	for _, tt := range tests {
+		if got := institution(tt.org); got != tt.want {
+			t.Errorf("institution(%q) = %q, want %q", tt.org, got, tt.want)
+		}
+	}
+}
+
+func TestWriteStatement_roundTrip(t *testing.T) {
+	data, err := os.ReadFile("../td/sample.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := td.ParseStatement(string(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := parsed.ToModel()
+
+	var buf bytes.Buffer
+	if err := WriteStatement(&buf, want); err != nil {
+		t.Fatalf("WriteStatement() error = %v", err)
+	}
+	statements, err := ReadStatements(&buf)
+	if err != nil {
+		t.Fatalf("ReadStatements() error = %v", err)
+	}
+	got := statements[0]
+
+	if got.Institution != want.Institution || got.AccountType != want.AccountType || got.AccountNumber != want.AccountNumber ||
+		!got.PeriodStartDate.Equal(want.PeriodStartDate) || !got.PeriodEndDate.Equal(want.PeriodEndDate) ||
+		got.BeginningBalance != want.BeginningBalance || got.EndingBalance != want.EndingBalance {
+		t.Errorf("round trip statement = %+v, want %+v", got, want)
+	}
+	if len(got.Transactions) != len(want.Transactions) {
+		t.Fatalf("round trip got %d transactions, want %d", len(got.Transactions), len(want.Transactions))
+	}
+
+	fitIDs := map[string]bool{}
+	for i, tx := range got.Transactions {
+		if tx.Amount != want.Transactions[i].Amount || tx.Description != want.Transactions[i].Description {
+			t.Errorf("round trip transaction %d = %+v, want %+v", i, tx, want.Transactions[i])
+		}
+		if fitIDs[tx.ReferenceNumber] {
+			t.Errorf("round trip transaction %d has a repeated fitid %s", i, tx.ReferenceNumber)
+		}
+		fitIDs[tx.ReferenceNumber] = true
+	}
+
+	// the fitids are the model ids the store and the other exports use
+	withIDs := want
+	withIDs.Transactions = append([]model.Transaction(nil), want.Transactions...)
+	withIDs.AssignIDs()
+	for i, id := range FITIDs(want) {
+		if id != withIDs.Transactions[i].ID {
+			t.Errorf("FITIDs()[%d] = %s, want the model id %s", i, id, withIDs.Transactions[i].ID)
+		}
+	}
+
+	// the fitids must stay the same when the statement is exported again
+	if ids := FITIDs(got); !reflect.DeepEqual(ids, FITIDs(want)) {
+		t.Errorf("FITIDs() after round trip = %v, want %v", ids, FITIDs(want))
+	}
+}
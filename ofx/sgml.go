@@ -0,0 +1,121 @@
+package ofx
+
+import (
+	"fmt"
+	"strings"
+)
+
+// node is an element of the ofx document, the leaf elements carry a value and the aggregates carry children
+type node struct {
+	name     string
+	value    string
+	children []*node
+}
+
+// child returns the first descendant following the given path of element names
+func (n *node) child(path ...string) *node {
+	current := n
+	for _, name := range path {
+		var next *node
+		for _, c := range current.children {
+			if c.name == name {
+				next = c
+				break
+			}
+		}
+		if next == nil {
+			return nil
+		}
+		current = next
+	}
+	return current
+}
+
+// text returns the value of the descendant following the path, or an empty string when it is missing
+func (n *node) text(path ...string) string {
+	if c := n.child(path...); c != nil {
+		return c.value
+	}
+	return ""
+}
+
+// findAll returns all the descendants having the name, in document order
+func (n *node) findAll(name string) []*node {
+	var found []*node
+	for _, c := range n.children {
+		if c.name == name {
+			found = append(found, c)
+		}
+		found = append(found, c.findAll(name)...)
+	}
+	return found
+}
+
+// parseDocument parses both the ofx 1.x sgml, where the leaf elements are not closed, and the ofx 2.x xml
+func parseDocument(data string) (*node, error) {
+	start := strings.Index(data, "<OFX>")
+	if start < 0 {
+		return nil, fmt.Errorf("missing <OFX> root element")
+	}
+	data = data[start:]
+
+	root := &node{}
+	stack := []*node{root}
+
+	for len(data) > 0 {
+		open := strings.IndexByte(data, '<')
+		if open < 0 {
+			break
+		}
+		if text := strings.TrimSpace(data[:open]); text != "" {
+			top := stack[len(stack)-1]
+			top.value = unescape(text)
+		}
+		end := strings.IndexByte(data[open:], '>')
+		if end < 0 {
+			return nil, fmt.Errorf("unterminated tag %q", data[open:])
+		}
+		tag := data[open+1 : open+end]
+		data = data[open+end+1:]
+
+		switch {
+		case strings.HasPrefix(tag, "?") || strings.HasPrefix(tag, "!"):
+			continue // processing instructions and comments
+		case strings.HasPrefix(tag, "/"):
+			name := strings.TrimSpace(tag[1:])
+			// pop up to the matching element, implicitly closing the sgml leaf elements
+			i := len(stack) - 1
+			for i > 0 && stack[i].name != name {
+				i--
+			}
+			if i == 0 {
+				return nil, fmt.Errorf("unexpected closing tag </%s>", name)
+			}
+			stack = stack[:i]
+		default:
+			top := stack[len(stack)-1]
+			// an open element with a value is a sgml leaf element, which ends where the next element starts
+			if top != root && top.value != "" && len(top.children) == 0 {
+				stack = stack[:len(stack)-1]
+				top = stack[len(stack)-1]
+			}
+			n := &node{name: strings.TrimSpace(strings.TrimSuffix(tag, "/"))}
+			top.children = append(top.children, n)
+			if !strings.HasSuffix(tag, "/") {
+				stack = append(stack, n)
+			}
+		}
+	}
+
+	ofx := root.child("OFX")
+	if ofx == nil {
+		return nil, fmt.Errorf("missing <OFX> root element")
+	}
+	return ofx, nil
+}
+
+var unescaper = strings.NewReplacer("&lt;", "<", "&gt;", ">", "&quot;", `"`, "&apos;", "'", "&nbsp;", " ", "&amp;", "&")
+
+func unescape(s string) string {
+	return unescaper.Replace(s)
+}
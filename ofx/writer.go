@@ -0,0 +1,172 @@
+package ofx
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/muly/bank-tx/model"
+	"github.com/muly/bank-tx/util"
+)
+
+// unknownBankID is written as the BANKID of the checking accounts, the statements do not carry the routing number
+const unknownBankID = "000000000"
+
+// maxNameLength is the ofx limit of the NAME element, the full description is written to MEMO when it is longer
+const maxNameLength = 32
+
+type transactionData struct {
+	Type   string
+	Posted string
+	User   string
+	Amount string
+	FITID  string
+	Name   string
+	Memo   string
+}
+
+type statementData struct {
+	Server       string
+	Org          string
+	CreditCard   bool
+	BankID       string
+	AccountID    string
+	Start        string
+	End          string
+	Transactions []transactionData
+	Balance      string
+}
+
+var ofxTemplate = template.Must(template.New("ofx").Funcs(template.FuncMap{"escape": escape}).Parse(`<?xml version="1.0" encoding="UTF-8" standalone="no"?>
+<?OFX OFXHEADER="200" VERSION="220" SECURITY="NONE" OLDFILEUID="NONE" NEWFILEUID="NONE"?>
+<OFX>
+  <SIGNONMSGSRSV1>
+    <SONRS>
+      <STATUS><CODE>0</CODE><SEVERITY>INFO</SEVERITY></STATUS>
+      <DTSERVER>{{.Server}}</DTSERVER>
+      <LANGUAGE>ENG</LANGUAGE>
+      <FI><ORG>{{escape .Org}}</ORG></FI>
+    </SONRS>
+  </SIGNONMSGSRSV1>
+{{- if .CreditCard}}
+  <CREDITCARDMSGSRSV1>
+    <CCSTMTTRNRS>
+      <TRNUID>0</TRNUID>
+      <STATUS><CODE>0</CODE><SEVERITY>INFO</SEVERITY></STATUS>
+      <CCSTMTRS>
+        <CURDEF>USD</CURDEF>
+        <CCACCTFROM><ACCTID>{{escape .AccountID}}</ACCTID></CCACCTFROM>
+{{- else}}
+  <BANKMSGSRSV1>
+    <STMTTRNRS>
+      <TRNUID>0</TRNUID>
+      <STATUS><CODE>0</CODE><SEVERITY>INFO</SEVERITY></STATUS>
+      <STMTRS>
+        <CURDEF>USD</CURDEF>
+        <BANKACCTFROM><BANKID>{{.BankID}}</BANKID><ACCTID>{{escape .AccountID}}</ACCTID><ACCTTYPE>CHECKING</ACCTTYPE></BANKACCTFROM>
+{{- end}}
+        <BANKTRANLIST>
+          <DTSTART>{{.Start}}</DTSTART>
+          <DTEND>{{.End}}</DTEND>
+{{- range .Transactions}}
+          <STMTTRN>
+            <TRNTYPE>{{.Type}}</TRNTYPE>
+            <DTPOSTED>{{.Posted}}</DTPOSTED>
+            <DTUSER>{{.User}}</DTUSER>
+            <TRNAMT>{{.Amount}}</TRNAMT>
+            <FITID>{{escape .FITID}}</FITID>
+            <NAME>{{escape .Name}}</NAME>
+{{- if .Memo}}
+            <MEMO>{{escape .Memo}}</MEMO>
+{{- end}}
+          </STMTTRN>
+{{- end}}
+        </BANKTRANLIST>
+        <LEDGERBAL><BALAMT>{{.Balance}}</BALAMT><DTASOF>{{.End}}</DTASOF></LEDGERBAL>
+{{- if .CreditCard}}
+      </CCSTMTRS>
+    </CCSTMTTRNRS>
+  </CREDITCARDMSGSRSV1>
+{{- else}}
+      </STMTRS>
+    </STMTTRNRS>
+  </BANKMSGSRSV1>
+{{- end}}
+</OFX>
+`))
+
+// WriteStatement writes the statement as an ofx 2.x file, CREDITCARDMSGSRSV1 for the credit cards and BANKMSGSRSV1
+// for the checking accounts, so it can be imported into the personal finance apps
+func WriteStatement(w io.Writer, s model.Statement) error {
+	if s.AccountType != model.Checking && s.AccountType != model.CreditCard {
+		return fmt.Errorf("unsupported account type %q", s.AccountType)
+	}
+
+	// ofx has the credit card amounts and balances from the point of view of the card holder
+	sign := 1.0
+	if s.AccountType == model.CreditCard {
+		sign = -1.0
+	}
+
+	data := statementData{
+		Server:     formatDate(s.PeriodEndDate),
+		Org:        strings.ToUpper(s.Institution),
+		CreditCard: s.AccountType == model.CreditCard,
+		BankID:     unknownBankID,
+		AccountID:  s.AccountNumber,
+		Start:      formatDate(s.PeriodStartDate),
+		End:        formatDate(s.PeriodEndDate),
+		Balance:    fmt.Sprintf("%.2f", util.RoundToTwoDecimal(s.EndingBalance*sign)),
+	}
+
+	fitIDs := FITIDs(s)
+	for i, tx := range s.Transactions {
+		amount := util.RoundToTwoDecimal(tx.Amount * sign)
+		t := transactionData{
+			Type:   "DEBIT",
+			Posted: formatDate(tx.PostingDate),
+			User:   formatDate(tx.TransactionDate),
+			Amount: fmt.Sprintf("%.2f", amount),
+			FITID:  fitIDs[i],
+			Name:   tx.Description,
+		}
+		if amount > 0 {
+			t.Type = "CREDIT"
+		}
+		if tx.TransactionDate.IsZero() {
+			t.User = t.Posted
+		}
+		if len(t.Name) > maxNameLength {
+			t.Name, t.Memo = strings.TrimSpace(t.Name[:maxNameLength]), tx.Description
+		}
+		data.Transactions = append(data.Transactions, t)
+	}
+
+	return ofxTemplate.Execute(w, data)
+}
+
+// FITIDs returns the stable financial institution transaction ids of the statement transactions, the model IDs,
+// see model.TransactionID, so a transaction has the same id in the ofx files and in the other exports and the store.
+// The transactions without an ID get theirs assigned the way the importers do.
+func FITIDs(s model.Statement) []string {
+	s.Transactions = append([]model.Transaction(nil), s.Transactions...)
+	s.AssignIDs()
+
+	ids := make([]string, 0, len(s.Transactions))
+	for _, tx := range s.Transactions {
+		ids = append(ids, tx.ID)
+	}
+	return ids
+}
+
+func formatDate(t time.Time) string {
+	return t.Format("20060102")
+}
+
+var escaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+func escape(s string) string {
+	return escaper.Replace(s)
+}
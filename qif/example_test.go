@@ -0,0 +1,93 @@
+package qif
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/muly/bank-tx/bofa_cc"
+)
+
+func ExampleWriteStatement() {
+	s, err := bofa_cc.ParseStatement(`
+Account# 4400 1234 5678 1234
+September 12 - October 11, 2024
+Previous Balance $1,905.57
+Payments and Other Credits -$1,905.57
+Purchases and Adjustments $42.75
+Fees Charged $0.00
+Interest Charged $0.00
+New Balance Total $42.75
+Payments and Other Credits
+09/28 09/30 PAYMENT - THANK YOU 0027 1234 -1,905.57
+Purchases and Adjustments
+09/13 09/16 ERERE RERE COUNTY SCHOOL FDFDDF-DDFDFD DF 0881 1234 42.75
+`)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	if err := WriteStatement(os.Stdout, s.ToModel()); err != nil {
+		fmt.Println(err)
+	}
+
+	// Output:
+	// !Account
+	// N4400 1234 5678 1234
+	// TCCard
+	// ^
+	// !Type:CCard
+	// D09/12/2024
+	// T-1905.57
+	// POpening Balance
+	// L[4400 1234 5678 1234]
+	// ^
+	// D09/30/2024
+	// T1905.57
+	// PPAYMENT - THANK YOU
+	// LPayments and Other Credits
+	// N0027
+	// ^
+	// D09/16/2024
+	// T-42.75
+	// PERERE RERE COUNTY SCHOOL FDFDDF-DDFDFD DF
+	// LPurchases and Adjustments
+	// N0881
+	// ^
+}
+
+func ExampleReadStatement() {
+	data := `!Type:Bank
+D03/21'23
+T10,750.35
+POpening Balance
+L[TD Checking]
+^
+D04/11'23
+T-500.00
+PTD BILL PAY SERV, BANK OF AMERICA ONLINE PMT
+LElectronic Payments
+^
+D04/02'23
+T6,377.30
+PACH DEPOSIT, rere erer ererereL
+LElectronic Deposits
+^
+`
+
+	s, err := ReadStatement(strings.NewReader(data), "123-4567890")
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(s.AccountType, s.PeriodStartDate.Format("2006-01-02"), s.PeriodEndDate.Format("2006-01-02"), s.BeginningBalance, s.EndingBalance)
+	for _, tx := range s.Transactions {
+		fmt.Println(tx.PostingDate.Format("2006-01-02"), tx.Description, tx.Amount, tx.Category)
+	}
+
+	// Output:
+	// checking 2023-03-21 2023-04-11 10750.35 16627.65
+	// 2023-04-02 ACH DEPOSIT, rere erer ererereL 6377.3 Electronic Deposits
+	// 2023-04-11 TD BILL PAY SERV, BANK OF AMERICA ONLINE PMT -500 Electronic Payments
+}
@@ -0,0 +1,201 @@
+// package qif provides the functions to write the statements as qif for the older quicken and microsoft money
+// workflows, and to read the legacy qif history into the bank neutral model
+package qif
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/muly/bank-tx/model"
+	"github.com/muly/bank-tx/util"
+)
+
+// Account type headers
+const (
+	TypeBank  = "!Type:Bank"
+	TypeCCard = "!Type:CCard"
+)
+
+// accountHeader starts the block naming the account of the records that follow
+const accountHeader = "!Account"
+
+// openingBalance is the payee quicken uses for the first record of an account
+const openingBalance = "Opening Balance"
+
+// categoryEscaper replaces the characters that qif uses to separate the subcategories and the classes
+var categoryEscaper = strings.NewReplacer(":", "-", "/", "-")
+
+// WriteStatement writes the statement as an !Account block naming the account, followed by a qif !Type:Bank or
+// !Type:CCard section with the "Opening Balance" record of the beginning balance and one record per transaction.
+// The category of the record is the statement section of the transaction.
+func WriteStatement(w io.Writer, s model.Statement) error {
+	header, accountType, sign := TypeBank, "Bank", 1.0
+	switch s.AccountType {
+	case model.Checking:
+	case model.CreditCard:
+		// qif has the charges as negative
+		header, accountType, sign = TypeCCard, "CCard", -1.0
+	default:
+		return fmt.Errorf("unsupported account type %q", s.AccountType)
+	}
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, accountHeader)
+	fmt.Fprintf(bw, "N%s\n", s.AccountNumber)
+	fmt.Fprintf(bw, "T%s\n", accountType)
+	fmt.Fprintln(bw, "^")
+	fmt.Fprintln(bw, header)
+	fmt.Fprintf(bw, "D%s\n", s.PeriodStartDate.Format("01/02/2006"))
+	fmt.Fprintf(bw, "T%.2f\n", util.RoundToTwoDecimal(s.BeginningBalance*sign)+0)
+	fmt.Fprintf(bw, "P%s\n", openingBalance)
+	fmt.Fprintf(bw, "L[%s]\n", s.AccountNumber)
+	fmt.Fprintln(bw, "^")
+	for _, tx := range s.Transactions {
+		fmt.Fprintf(bw, "D%s\n", tx.PostingDate.Format("01/02/2006"))
+		fmt.Fprintf(bw, "T%.2f\n", util.RoundToTwoDecimal(tx.Amount*sign))
+		fmt.Fprintf(bw, "P%s\n", tx.Description)
+		if tx.Category != "" {
+			fmt.Fprintf(bw, "L%s\n", categoryEscaper.Replace(tx.Category))
+		}
+		if tx.ReferenceNumber != "" {
+			fmt.Fprintf(bw, "N%s\n", tx.ReferenceNumber)
+		}
+		fmt.Fprintln(bw, "^")
+	}
+	return bw.Flush()
+}
+
+// ReadStatement reads the records of a qif !Type:Bank or !Type:CCard section into a statement.
+// The period spans the first to the last record, an "Opening Balance" record sets the beginning balance.
+// The account number is the name of the !Account block when it is empty.
+func ReadStatement(r io.Reader, accountNumber string) (*model.Statement, error) {
+	statement := model.Statement{
+		AccountNumber: accountNumber,
+	}
+	sign := 1.0
+	openingBalanceFound := false
+
+	var tx model.Transaction
+	var payee string
+	var hasDate bool
+	// inAccount is set within an !Account block, its fields describe the account, not a transaction
+	inAccount := false
+
+	scanner := bufio.NewScanner(r)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		if strings.HasPrefix(line, "!") {
+			switch strings.TrimSpace(line) {
+			case TypeBank, "!Type:Cash":
+				statement.AccountType, sign = model.Checking, 1.0
+			case TypeCCard:
+				statement.AccountType, sign = model.CreditCard, -1.0
+			case accountHeader:
+				inAccount = true
+			case "!Option:AutoSwitch", "!Clear:AutoSwitch":
+			default:
+				return nil, fmt.Errorf("line %d: unsupported qif section %s", lineNumber, line)
+			}
+			continue
+		}
+
+		code, value := line[0], strings.TrimSpace(line[1:])
+		if inAccount {
+			switch code {
+			case 'N':
+				if accountNumber == "" {
+					statement.AccountNumber = value
+				}
+			case '^':
+				inAccount = false
+			}
+			continue
+		}
+
+		switch code {
+		case 'D':
+			date, err := parseDate(value)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %v", lineNumber, err)
+			}
+			tx.TransactionDate, tx.PostingDate, hasDate = date, date, true
+		case 'T', 'U':
+			amount, err := util.ParseFloat(value)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: failed to parse amount: %s, error: %v", lineNumber, value, err)
+			}
+			tx.Amount = amount * sign
+		case 'P':
+			payee = value
+		case 'M':
+			if tx.Description == "" {
+				tx.Description = value
+			}
+		case 'L':
+			tx.Category = value
+		case 'N':
+			tx.ReferenceNumber = value
+		case '^':
+			if !hasDate {
+				return nil, fmt.Errorf("line %d: record without a date", lineNumber)
+			}
+			if payee != "" {
+				tx.Description = payee
+			}
+			if payee == openingBalance && len(statement.Transactions) == 0 && !openingBalanceFound {
+				statement.BeginningBalance = tx.Amount
+				statement.PeriodStartDate = tx.PostingDate
+				openingBalanceFound = true
+			} else {
+				statement.Transactions = append(statement.Transactions, tx)
+			}
+			tx, payee, hasDate = model.Transaction{}, "", false
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if statement.AccountType == "" {
+		return nil, fmt.Errorf("missing %s or %s header", TypeBank, TypeCCard)
+	}
+	if len(statement.Transactions) == 0 {
+		return nil, fmt.Errorf("no transactions in qif")
+	}
+
+	sort.SliceStable(statement.Transactions, func(i, j int) bool {
+		return statement.Transactions[i].PostingDate.Before(statement.Transactions[j].PostingDate)
+	})
+	if !openingBalanceFound {
+		statement.PeriodStartDate = statement.Transactions[0].PostingDate
+	}
+	statement.PeriodEndDate = statement.Transactions[len(statement.Transactions)-1].PostingDate
+	statement.EndingBalance = util.RoundToTwoDecimal(statement.BeginningBalance + statement.Total())
+
+	return &statement, nil
+}
+
+// parseDate parses the qif dates like "04/11/2023", "4/11'23", "12/ 1' 9", "04/11/98" and "04-11-2023".
+// Quicken writes the years since 2000 after an apostrophe.
+func parseDate(s string) (time.Time, error) {
+	s = strings.NewReplacer("-", "/", " ", "").Replace(s)
+	if monthDay, year, ok := strings.Cut(s, "'"); ok {
+		s = fmt.Sprintf("%s/20%02s", monthDay, year)
+	}
+	for _, layout := range []string{"1/2/2006", "1/2/06"} {
+		if date, err := time.Parse(layout, s); err == nil {
+			return date, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q", s)
+}
@@ -0,0 +1,106 @@
+package qif
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/muly/bank-tx/model"
+)
+
+func Test_parseDate(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       string
+		want    time.Time
+		wantErr bool
+	}{
+		{name: "case 1: four digit year", s: "04/11/2023", want: time.Date(2023, 4, 11, 0, 0, 0, 0, time.UTC)},
+		{name: "case 2: quicken apostrophe year", s: "4/11'23", want: time.Date(2023, 4, 11, 0, 0, 0, 0, time.UTC)},
+		{name: "case 3: apostrophe year with space", s: "12/ 1' 9", want: time.Date(2009, 12, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "case 4: two digit year", s: "04/11/98", want: time.Date(1998, 4, 11, 0, 0, 0, 0, time.UTC)},
+		{name: "case 5: dashes", s: "04-11-2023", want: time.Date(2023, 4, 11, 0, 0, 0, 0, time.UTC)},
+		{name: "case 6: invalid", s: "2023-04", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseDate(tt.s)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parseDate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("parseDate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadStatement(t *testing.T) {
+	records := "!Type:Bank\nD03/21'23\nT10,750.35\nPOpening Balance\nL[TD Checking]\n^\nD04/02'23\nT6,377.30\nPACH DEPOSIT\nN1001\n^\n"
+	tests := []struct {
+		name              string
+		data              string
+		accountNumber     string
+		wantAccountNumber string
+		wantErr           string
+	}{
+		{name: "case 1: account block", data: "!Account\nNTD Checking\nTBank\n^\n" + records, wantAccountNumber: "TD Checking"},
+		{name: "case 2: account block with autoswitch", data: "!Option:AutoSwitch\n!Account\nNTD Checking\nTBank\nDMain account\n^\n!Clear:AutoSwitch\n" + records, wantAccountNumber: "TD Checking"},
+		{name: "case 3: account number given", data: "!Account\nNTD Checking\nTBank\n^\n" + records, accountNumber: "123-4567890", wantAccountNumber: "123-4567890"},
+		{name: "case 4: no account block", data: records, wantAccountNumber: ""},
+		{name: "case 5: record without a date", data: "!Type:Bank\nT10.00\n^\n", wantErr: "line 3: record without a date"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ReadStatement(strings.NewReader(tt.data), tt.accountNumber)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("ReadStatement() error = %v, want %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ReadStatement() error = %v", err)
+			}
+			if got.AccountNumber != tt.wantAccountNumber {
+				t.Errorf("ReadStatement() account number = %q, want %q", got.AccountNumber, tt.wantAccountNumber)
+			}
+			if got.BeginningBalance != 10750.35 || len(got.Transactions) != 1 || got.Transactions[0].ReferenceNumber != "1001" {
+				t.Errorf("ReadStatement() = %+v, want the opening balance and the deposit", got)
+			}
+		})
+	}
+}
+
+func TestWriteStatement_roundTrip(t *testing.T) {
+	date := func(day int) time.Time { return time.Date(2024, 9, day, 0, 0, 0, 0, time.UTC) }
+	for _, want := range []model.Statement{
+		{AccountType: model.Checking, AccountNumber: "123-4567890", PeriodStartDate: date(1), PeriodEndDate: date(20),
+			BeginningBalance: 1000, EndingBalance: 1450, Transactions: []model.Transaction{
+				{TransactionDate: date(5), PostingDate: date(5), Description: "ACH DEPOSIT", Amount: 500},
+				{TransactionDate: date(20), PostingDate: date(20), Description: "CHECK", Amount: -50, ReferenceNumber: "1024"},
+			}},
+		{AccountType: model.CreditCard, AccountNumber: "4400 1234 5678 1234", PeriodStartDate: date(12), PeriodEndDate: date(16),
+			BeginningBalance: 1905.57, EndingBalance: 42.75, Transactions: []model.Transaction{
+				{TransactionDate: date(13), PostingDate: date(13), Description: "PAYMENT - THANK YOU", Amount: -1905.57},
+				{TransactionDate: date(16), PostingDate: date(16), Description: "ERERE RERE COUNTY SCHOOL", Amount: 42.75},
+			}},
+	} {
+		t.Run(want.AccountType, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := WriteStatement(&buf, want); err != nil {
+				t.Fatalf("WriteStatement() error = %v", err)
+			}
+			got, err := ReadStatement(&buf, "")
+			if err != nil {
+				t.Fatalf("ReadStatement() error = %v", err)
+			}
+			if got.AccountNumber != want.AccountNumber || got.BeginningBalance != want.BeginningBalance || got.EndingBalance != want.EndingBalance ||
+				!got.PeriodStartDate.Equal(want.PeriodStartDate) || !got.PeriodEndDate.Equal(want.PeriodEndDate) || len(got.Transactions) != len(want.Transactions) {
+				t.Errorf("round trip = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/muly/bank-tx/analysis"
+	"github.com/muly/bank-tx/merchant"
+	"github.com/muly/bank-tx/store"
+)
+
+// runRecurring lists the recurring charges and deposits of the transactions in the store with their alerts
+func runRecurring(args []string) error {
+	flags := flag.NewFlagSet("recurring", flag.ExitOnError)
+	db := flags.String("db", "bank-tx.db", "sqlite database file")
+	aliases := flags.String("aliases", "", "yaml or json merchant alias file")
+	minOccurrences := flags.Int("min", 3, "number of the transactions a recurring series needs")
+	flags.Parse(args)
+
+	p := analysis.Policy{MinOccurrences: *minOccurrences}
+	if *aliases != "" {
+		n, err := merchant.Load(*aliases)
+		if err != nil {
+			return err
+		}
+		p.Normalizer = n
+	}
+
+	s, err := store.Open(*db)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+
+	statements, err := s.Statements()
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ACCOUNT\tMERCHANT\tDIRECTION\tCADENCE\tAMOUNT\tTIMES\tLAST\tNEXT")
+	series := analysis.Recurring(statements, p)
+	for _, r := range series {
+		fmt.Fprintf(w, "%s %s\t%s\t%s\t%s\t%.2f\t%d\t%s\t%s\n", r.Institution, r.AccountNumber, r.Merchant, r.Direction, r.Cadence.Name,
+			r.Amount, len(r.Occurrences), r.Occurrences[len(r.Occurrences)-1].Date.Format("2006-01-02"), r.Next.Format("2006-01-02"))
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	for _, r := range series {
+		for _, alert := range r.Alerts {
+			fmt.Printf("%s %s: %s\n", alert.Date.Format("2006-01-02"), alert.Kind, alert.Message)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"regexp"
+
+	"github.com/muly/bank-tx/merchant"
+	"github.com/muly/bank-tx/report"
+	"github.com/muly/bank-tx/store"
+)
+
+// runReport writes the spending report of the transactions in the store
+func runReport(args []string) error {
+	flags := flag.NewFlagSet("report", flag.ExitOnError)
+	db := flags.String("db", "bank-tx.db", "sqlite database file")
+	format := flags.String("format", report.Text, "output format: text, csv or markdown")
+	depth := flags.Int("depth", 0, "number of the category levels reported, 0 for all")
+	top := flags.Int("top", 10, "number of the merchants listed")
+	aliases := flags.String("aliases", "", "yaml or json merchant alias file")
+	exclude := flags.String("exclude", report.DefaultExclude, "regexp of the descriptions left out, the card payments whose card is not in the store")
+	flags.Parse(args)
+
+	// the transfers matched at import are left out by their TransferID, the pattern is for the payments to the cards not imported
+	o := report.Options{Depth: *depth, Top: *top}
+	if *exclude != "" {
+		re, err := regexp.Compile(*exclude)
+		if err != nil {
+			return fmt.Errorf("invalid -exclude: %v", err)
+		}
+		o.Exclude = re
+	}
+	if *aliases != "" {
+		n, err := merchant.Load(*aliases)
+		if err != nil {
+			return err
+		}
+		o.Normalizer = n
+	}
+
+	s, err := store.Open(*db)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+
+	statements, err := s.Statements()
+	if err != nil {
+		return err
+	}
+
+	return report.Write(os.Stdout, *format, report.NewSpending(statements, o).Tables()...)
+}
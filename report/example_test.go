@@ -0,0 +1,67 @@
+package report
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/muly/bank-tx/model"
+)
+
+func ExampleSpending_Tables() {
+	day := func(year int, month time.Month, d int) time.Time {
+		return time.Date(year, month, d, 0, 0, 0, 0, time.UTC)
+	}
+	card := func(year int, month time.Month, transactions ...model.Transaction) model.Statement {
+		return model.Statement{Institution: model.BofA, AccountType: model.CreditCard, AccountNumber: "1234",
+			PeriodStartDate: day(year, month, 1), PeriodEndDate: day(year, month+1, 0), Transactions: transactions}
+	}
+
+	statements := []model.Statement{
+		card(2023, time.September,
+			model.Transaction{PostingDate: day(2023, 9, 9), Description: "COSTCO WHSE #1111 TOWN STATE", Amount: 120, SpendingCategory: "Food:Groceries"},
+		),
+		card(2024, time.August,
+			model.Transaction{PostingDate: day(2024, 8, 2), Description: "COSTCO WHSE #1111 TOWN STATE", Amount: 180.25, SpendingCategory: "Food:Groceries"},
+			model.Transaction{PostingDate: day(2024, 8, 20), Description: "THE HOME DEPOT #3644 TOWN STATE", Amount: 64.70, SpendingCategory: "Home:Improvement"},
+		),
+		card(2024, time.September,
+			model.Transaction{PostingDate: day(2024, 9, 3), Description: "PAYMENT - THANK YOU", Amount: -244.95, TransferID: "td-payment"},
+			model.Transaction{PostingDate: day(2024, 9, 20), Description: "COSTCO WHSE #1206 TOWN STATE", Amount: 142.13, SpendingCategory: "Food:Groceries"},
+			model.Transaction{PostingDate: day(2024, 9, 23), Description: "TST*WATERPARK - KIOSK 1 TOWN STATE", Amount: 14.90, SpendingCategory: "Food:Dining"},
+			model.Transaction{PostingDate: day(2024, 9, 26), Description: "HOMEDEPOT.COM 111-111-1111 BC", Amount: 54.92},
+			model.Transaction{PostingDate: day(2024, 9, 27), Description: "THE HOME DEPOT #3644 TOWN STATE", Amount: -20, SpendingCategory: "Home:Improvement"},
+		),
+	}
+
+	s := NewSpending(statements, Options{Depth: 1, Top: 3})
+	if err := Write(os.Stdout, Markdown, s.Tables()...); err != nil {
+		fmt.Println(err)
+	}
+
+	// Output:
+	// ## Spending by category and month
+	//
+	// | Category | 2023-09 | 2024-08 | 2024-09 | Total | Average |
+	// |---|---|---|---|---|---|
+	// | Food | 120.00 | 180.25 | 157.03 | 457.28 | 152.43 |
+	// | Home | 0.00 | 64.70 | 0.00 | 64.70 | 21.57 |
+	// | Uncategorized | 0.00 | 0.00 | 54.92 | 54.92 | 18.31 |
+	// | Total | 120.00 | 244.95 | 211.95 | 576.90 | 192.30 |
+	//
+	// ## Monthly spending
+	//
+	// | Month | Total | Transactions | Average | MoM | MoM % | YoY | YoY % |
+	// |---|---|---|---|---|---|---|---|
+	// | 2023-09 | 120.00 | 1 | 120.00 |  |  |  |  |
+	// | 2024-08 | 244.95 | 2 | 122.48 |  |  |  |  |
+	// | 2024-09 | 211.95 | 3 | 70.65 | -33.00 | -13.5% | +91.95 | +76.6% |
+	//
+	// ## Top 3 merchants
+	//
+	// | Merchant | Total | Transactions | Average | Share |
+	// |---|---|---|---|---|
+	// | COSTCO WHSE | 442.38 | 3 | 147.46 | 76.7% |
+	// | THE HOME DEPOT | 64.70 | 1 | 64.70 | 11.2% |
+	// | HOMEDEPOT.COM | 54.92 | 1 | 54.92 | 9.5% |
+}
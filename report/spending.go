@@ -0,0 +1,245 @@
+package report
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/muly/bank-tx/categorize"
+	"github.com/muly/bank-tx/merchant"
+	"github.com/muly/bank-tx/model"
+	"github.com/muly/bank-tx/util"
+)
+
+// Uncategorized is the category of the transactions without a spending category
+const Uncategorized = "Uncategorized"
+
+// DefaultExclude matches the bofa card payments from checking, the payments to a card whose statements are not in the store
+// have no TransferID. The payees of the other card payment patterns, like AUTOPAY, are also the bills paid automatically.
+const DefaultExclude = `(?i)BANK OF AMERICA|BOFA`
+
+// Options configures the spending report
+type Options struct {
+	// Depth is the number of the category levels kept, e.g. 1 reports "Food:Groceries" as "Food", 0 keeps all of them
+	Depth int
+	// Top is the number of the merchants listed, 10 by default
+	Top int
+	// Normalizer groups the transactions by the canonical merchant, the merchants are parsed without the aliases when nil
+	Normalizer *merchant.Normalizer
+	// Exclude matches the descriptions left out, e.g. the card payments whose other side is not in the statements, see DefaultExclude
+	Exclude *regexp.Regexp
+}
+
+// Total is the spending of a group of the transactions
+type Total struct {
+	Amount float64
+	Count  int
+}
+
+// Average returns the average transaction size
+func (t Total) Average() float64 {
+	if t.Count == 0 {
+		return 0
+	}
+	return t.Amount / float64(t.Count)
+}
+
+func (t *Total) add(amount float64) {
+	t.Amount += amount
+	t.Count++
+}
+
+// MerchantTotal is the spending at a merchant
+type MerchantTotal struct {
+	Merchant string
+	Total
+}
+
+// Spending is the spending by month, "2006-01", and category. The spending is the money leaving the accounts,
+// the payments from checking and the purchases on the cards, without the transfers between the accounts:
+// the transactions with a TransferID, see transfer.Mark, those in the "Transfer" category and those matching
+// Options.Exclude are left out. The refunds and the income are not spending.
+type Spending struct {
+	Months     []string
+	Categories []string
+	// Cells holds the totals by category and month
+	Cells     map[string]map[string]Total
+	ByMonth   map[string]Total
+	Merchants []MerchantTotal
+	Total     Total
+	top       int
+}
+
+// NewSpending sums the spending of the statements
+func NewSpending(statements []model.Statement, o Options) *Spending {
+	if o.Top == 0 {
+		o.Top = 10
+	}
+	s := &Spending{Cells: map[string]map[string]Total{}, ByMonth: map[string]Total{}, top: o.Top}
+	merchants := map[string]*MerchantTotal{}
+
+	for _, statement := range statements {
+		for _, tx := range statement.Transactions {
+			flow := statement.Flow(tx)
+			if tx.TransferID != "" || flow >= 0 || categorize.Top(tx.SpendingCategory) == "Transfer" ||
+				(o.Exclude != nil && o.Exclude.MatchString(tx.Description)) {
+				continue
+			}
+			amount := -flow
+
+			month := tx.PostingDate.Format("2006-01")
+			category := truncate(tx.SpendingCategory, o.Depth)
+			if s.Cells[category] == nil {
+				s.Cells[category] = map[string]Total{}
+			}
+			cell := s.Cells[category][month]
+			cell.add(amount)
+			s.Cells[category][month] = cell
+
+			total := s.ByMonth[month]
+			total.add(amount)
+			s.ByMonth[month] = total
+			s.Total.add(amount)
+
+			name := o.Normalizer.Normalize(tx.Description).Merchant
+			if merchants[name] == nil {
+				merchants[name] = &MerchantTotal{Merchant: name}
+			}
+			merchants[name].add(amount)
+		}
+	}
+
+	for month := range s.ByMonth {
+		s.Months = append(s.Months, month)
+	}
+	sort.Strings(s.Months)
+	for category := range s.Cells {
+		s.Categories = append(s.Categories, category)
+	}
+	sort.Strings(s.Categories)
+	for _, m := range merchants {
+		s.Merchants = append(s.Merchants, *m)
+	}
+	sort.Slice(s.Merchants, func(i, j int) bool {
+		if s.Merchants[i].Amount != s.Merchants[j].Amount {
+			return s.Merchants[i].Amount > s.Merchants[j].Amount
+		}
+		return s.Merchants[i].Merchant < s.Merchants[j].Merchant
+	})
+	return s
+}
+
+// truncate returns the first levels of the category
+func truncate(category string, depth int) string {
+	if category == "" {
+		return Uncategorized
+	}
+	if depth <= 0 {
+		return category
+	}
+	levels := strings.Split(category, ":")
+	if len(levels) > depth {
+		levels = levels[:depth]
+	}
+	return strings.Join(levels, ":")
+}
+
+// Tables returns the month by category pivot table, the monthly totals with the deltas and the top merchants
+func (s *Spending) Tables() []Table {
+	return []Table{s.Pivot(), s.Monthly(), s.TopMerchants()}
+}
+
+// Pivot returns the totals of the categories by month, with the total and the monthly average of every category
+func (s *Spending) Pivot() Table {
+	t := Table{Title: "Spending by category and month", Header: append(append([]string{"Category"}, s.Months...), "Total", "Average")}
+	for _, category := range s.Categories {
+		row := []string{category}
+		var total Total
+		for _, month := range s.Months {
+			cell := s.Cells[category][month]
+			total.Amount += cell.Amount
+			row = append(row, Money(cell.Amount))
+		}
+		row = append(row, Money(total.Amount), Money(total.Amount/float64(len(s.Months))))
+		t.Rows = append(t.Rows, row)
+	}
+
+	row := []string{"Total"}
+	for _, month := range s.Months {
+		row = append(row, Money(s.ByMonth[month].Amount))
+	}
+	average := 0.0
+	if len(s.Months) > 0 {
+		average = s.Total.Amount / float64(len(s.Months))
+	}
+	t.Rows = append(t.Rows, append(row, Money(s.Total.Amount), Money(average)))
+	return t
+}
+
+// Monthly returns the total, the number and the average size of the transactions of every month, with the
+// month over month and the year over year changes of the total
+func (s *Spending) Monthly() Table {
+	t := Table{Title: "Monthly spending", Header: []string{"Month", "Total", "Transactions", "Average", "MoM", "MoM %", "YoY", "YoY %"}}
+	for _, month := range s.Months {
+		total := s.ByMonth[month]
+		row := []string{month, Money(total.Amount), fmt.Sprint(total.Count), Money(total.Average())}
+		row = append(row, s.delta(month, 0, -1)...)
+		row = append(row, s.delta(month, -1, 0)...)
+		t.Rows = append(t.Rows, row)
+	}
+	return t
+}
+
+// delta returns the change of the month total from the month the years and months before it, empty when that month
+// is not in the report
+func (s *Spending) delta(month string, years, months int) []string {
+	date, err := time.Parse("2006-01", month)
+	if err != nil {
+		return []string{"", ""}
+	}
+	previous := date.AddDate(years, months, 0).Format("2006-01")
+	if _, ok := s.ByMonth[previous]; !ok {
+		return []string{"", ""}
+	}
+	current, before := s.ByMonth[month].Amount, s.ByMonth[previous].Amount
+	return []string{signed(current - before), percent(current, before)}
+}
+
+// TopMerchants returns the merchants with the most spending
+func (s *Spending) TopMerchants() Table {
+	t := Table{Title: fmt.Sprintf("Top %d merchants", s.top), Header: []string{"Merchant", "Total", "Transactions", "Average", "Share"}}
+	for i, m := range s.Merchants {
+		if i == s.top {
+			break
+		}
+		t.Rows = append(t.Rows, []string{m.Merchant, Money(m.Amount), fmt.Sprint(m.Count), Money(m.Average()), percentOf(m.Amount, s.Total.Amount)})
+	}
+	return t
+}
+
+// Money formats the amount with two decimals, without the negative zero
+func Money(v float64) string {
+	return fmt.Sprintf("%.2f", util.RoundToTwoDecimal(v)+0)
+}
+
+func signed(v float64) string {
+	return fmt.Sprintf("%+.2f", util.RoundToTwoDecimal(v)+0)
+}
+
+// percent returns the change from before to current in percent
+func percent(current, before float64) string {
+	if before == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%+.1f%%", (current/before-1)*100)
+}
+
+// percentOf returns the share of the part in the whole in percent
+func percentOf(part, whole float64) string {
+	if whole == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%.1f%%", part/whole*100)
+}
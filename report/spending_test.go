@@ -0,0 +1,56 @@
+package report
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/muly/bank-tx/model"
+	"github.com/muly/bank-tx/util"
+)
+
+func TestNewSpendingExcludes(t *testing.T) {
+	date := time.Date(2024, 9, 16, 0, 0, 0, 0, time.UTC)
+	checking := model.Statement{Institution: model.TD, AccountType: model.Checking, AccountNumber: "123-4567890", Transactions: []model.Transaction{
+		{PostingDate: date, Description: "ELECTRONIC PMT-WEB, EEERERERERE MTG PAYMENTS ****311244", Amount: -6208.46, SpendingCategory: "Home:Mortgage"},
+		{PostingDate: date, Description: "TD BILL PAY SERV, BANK OF AMERICA ONLINE PMT TDB****34454454", Amount: -500},
+		{PostingDate: date, Description: "ELECTRONIC PMT-WEB, REREREER CK WEBXFR TRANSFER ****062167", Amount: -1000, SpendingCategory: "Transfer:Savings"},
+		{PostingDate: date, Description: "ELECTRONIC PMT-WEB, CARD PAYMENT", Amount: -250, TransferID: "card"},
+		{PostingDate: date, Description: "ACH DEPOSIT, ACME CORP PAYROLL", Amount: 6377.30},
+		{PostingDate: date, Description: "COMCAST AUTOPAY", Amount: -89.99, SpendingCategory: "Bills:Internet"},
+		{PostingDate: date, Description: "GEICO EPAY", Amount: -120.00, SpendingCategory: "Bills:Insurance"},
+	}}
+	card := model.Statement{Institution: model.BofA, AccountType: model.CreditCard, AccountNumber: "1234", Transactions: []model.Transaction{
+		{PostingDate: date, Description: "COSTCO WHSE #1111 TOWN STATE", Amount: 120.50, SpendingCategory: "Food:Groceries"},
+		{PostingDate: date, Description: "PAYMENT - THANK YOU", Amount: -250, TransferID: "checking"},
+		{PostingDate: date, Description: "COSTCO WHSE #1111 TOWN STATE", Amount: -20, SpendingCategory: "Food:Groceries"},
+	}}
+
+	// the bills paid automatically are spending, only the card payments are left out
+	s := NewSpending([]model.Statement{checking, card}, Options{Exclude: regexp.MustCompile(DefaultExclude)})
+	if s.Total.Count != 4 || util.RoundToTwoDecimal(s.Total.Amount) != 6538.95 {
+		t.Errorf("expected 4 transactions of 6538.95, got %d of %v", s.Total.Count, s.Total.Amount)
+	}
+	expected := []string{"Bills:Insurance", "Bills:Internet", "Food:Groceries", "Home:Mortgage"}
+	if !reflect.DeepEqual(s.Categories, expected) {
+		t.Errorf("expected categories %v, got %v", expected, s.Categories)
+	}
+}
+
+func TestMoney(t *testing.T) {
+	tests := []struct {
+		value    float64
+		expected string
+	}{
+		{6328.955, "6328.96"},
+		{-0.001, "0.00"},
+		{-20, "-20.00"},
+	}
+
+	for _, tt := range tests {
+		if actual := Money(tt.value); actual != tt.expected {
+			t.Errorf("Money(%v): expected %s, got %s", tt.value, tt.expected, actual)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+// package report provides the spending reports of the transactions, pivot tables written as terminal tables,
+// csv or markdown
+package report
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+// Formats of the tables
+const (
+	Text     = "text"
+	CSV      = "csv"
+	Markdown = "markdown"
+)
+
+// Table is a titled table of the report, the cells are formatted
+type Table struct {
+	Title  string
+	Header []string
+	Rows   [][]string
+}
+
+// Write writes the tables in the format, separated by a blank line
+func Write(w io.Writer, format string, tables ...Table) error {
+	for i, t := range tables {
+		if i > 0 {
+			if _, err := fmt.Fprintln(w); err != nil {
+				return err
+			}
+		}
+
+		var err error
+		switch format {
+		case Text, "":
+			err = t.WriteText(w)
+		case CSV:
+			err = t.WriteCSV(w)
+		case Markdown:
+			err = t.WriteMarkdown(w)
+		default:
+			return fmt.Errorf("unknown format %q, expected %s, %s or %s", format, Text, CSV, Markdown)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteText writes the table aligned for the terminal
+func (t Table) WriteText(w io.Writer) error {
+	if _, err := fmt.Fprintln(w, t.Title); err != nil {
+		return err
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.ToUpper(strings.Join(t.Header, "\t")))
+	for _, row := range t.Rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
+
+// WriteCSV writes the table as csv, the title is the first record
+func (t Table) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{t.Title}); err != nil {
+		return err
+	}
+	if err := writer.Write(t.Header); err != nil {
+		return err
+	}
+	if err := writer.WriteAll(t.Rows); err != nil {
+		return err
+	}
+	return writer.Error()
+}
+
+// WriteMarkdown writes the table as a markdown table under a heading of the title
+func (t Table) WriteMarkdown(w io.Writer) error {
+	escape := func(cells []string) string {
+		escaped := make([]string, len(cells))
+		for i, cell := range cells {
+			escaped[i] = strings.ReplaceAll(cell, "|", `\|`)
+		}
+		return "| " + strings.Join(escaped, " | ") + " |\n"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "## %s\n\n", t.Title)
+	b.WriteString(escape(t.Header))
+	separator := make([]string, len(t.Header))
+	for i := range separator {
+		separator[i] = "---"
+	}
+	b.WriteString("|" + strings.Join(separator, "|") + "|\n")
+	for _, row := range t.Rows {
+		b.WriteString(escape(row))
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,35 @@
+package report
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWrite(t *testing.T) {
+	table := Table{Title: "Top merchants", Header: []string{"Merchant", "Total"}, Rows: [][]string{{"A|B, INC", "10.00"}, {"COSTCO", "5.50"}}}
+
+	tests := []struct {
+		format   string
+		expected string
+	}{
+		{Text, "Top merchants\nMERCHANT  TOTAL\nA|B, INC  10.00\nCOSTCO    5.50\n"},
+		{CSV, "Top merchants\nMerchant,Total\n\"A|B, INC\",10.00\nCOSTCO,5.50\n"},
+		{Markdown, "## Top merchants\n\n| Merchant | Total |\n|---|---|\n| A\\|B, INC | 10.00 |\n| COSTCO | 5.50 |\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			var b bytes.Buffer
+			if err := Write(&b, tt.format, table); err != nil {
+				t.Fatal(err)
+			}
+			if b.String() != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, b.String())
+			}
+		})
+	}
+
+	if err := Write(&bytes.Buffer{}, "html", table); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
@@ -0,0 +1,58 @@
+package store
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/muly/bank-tx/td"
+)
+
+func ExampleStore_ImportStatement() {
+	dir, err := os.MkdirTemp("", "store")
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	s, err := Open(filepath.Join(dir, "bank-tx.db"))
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer s.Close()
+
+	data, err := os.ReadFile("../td/sample.txt")
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	statement, err := td.ParseStatement(string(data))
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	// importing the same statement again does not duplicate its transactions
+	for i := 0; i < 2; i++ {
+		result, err := s.ImportStatement(statement.ToModel())
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		fmt.Printf("%+v\n", result)
+	}
+
+	var count int
+	if err := s.DB().QueryRow(`SELECT COUNT(*) FROM transactions`).Scan(&count); err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(count, "transactions")
+
+	// Output:
+	// {StatementID:1 Created:true Transactions:11}
+	// {StatementID:1 Created:false Transactions:11}
+	// 11 transactions
+}
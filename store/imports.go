@@ -0,0 +1,153 @@
+package store
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+)
+
+// Import statuses
+const (
+	ImportOK     = "ok"
+	ImportFailed = "failed"
+)
+
+// Import is a record of the import history of a source file
+type Import struct {
+	ID            int64
+	Path          string
+	SHA256        string
+	Format        string
+	ParserVersion string
+	ImportedAt    time.Time
+	Status        string
+	Error         string
+	Statements    int
+}
+
+// Hash returns the hex sha256 of the file content, the fingerprint of the source file
+func Hash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// RecordImport adds the import to the history, the time of the import is set when missing
+func (s *Store) RecordImport(i Import) (int64, error) {
+	if i.ImportedAt.IsZero() {
+		i.ImportedAt = time.Now()
+	}
+	res, err := s.db.Exec(`INSERT INTO imports (path, sha256, format, parser_version, imported_at, status, error, statements)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		i.Path, i.SHA256, i.Format, i.ParserVersion, i.ImportedAt.UTC().Format(time.RFC3339), i.Status, i.Error, i.Statements)
+	if err != nil {
+		return 0, fmt.Errorf("failed to record import of %s: %v", i.Path, err)
+	}
+	return res.LastInsertId()
+}
+
+// ImportedOK tells if the content was already imported successfully by the parser version, so the file can be skipped
+func (s *Store) ImportedOK(hash, parserVersion string) (bool, error) {
+	var count int
+	err := s.db.QueryRow(`SELECT COUNT(*) FROM imports WHERE sha256 = ? AND parser_version = ? AND status = ?`,
+		hash, parserVersion, ImportOK).Scan(&count)
+	return count > 0, err
+}
+
+// LastImport returns the latest import of the path, nil when the path was never imported
+func (s *Store) LastImport(path string) (*Import, error) {
+	imports, err := s.queryImports(`WHERE path = ? ORDER BY id DESC LIMIT 1`, path)
+	if err != nil || len(imports) == 0 {
+		return nil, err
+	}
+	return &imports[0], nil
+}
+
+// Imports returns the import history, oldest first
+func (s *Store) Imports() ([]Import, error) {
+	return s.queryImports(`ORDER BY id`)
+}
+
+func (s *Store) queryImports(where string, args ...interface{}) ([]Import, error) {
+	rows, err := s.db.Query(`SELECT id, path, sha256, format, parser_version, imported_at, status, error, statements FROM imports `+where, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var imports []Import
+	for rows.Next() {
+		var i Import
+		var importedAt string
+		if err := rows.Scan(&i.ID, &i.Path, &i.SHA256, &i.Format, &i.ParserVersion, &importedAt, &i.Status, &i.Error, &i.Statements); err != nil {
+			return nil, err
+		}
+		if i.ImportedAt, err = time.Parse(time.RFC3339, importedAt); err != nil {
+			return nil, err
+		}
+		imports = append(imports, i)
+	}
+	return imports, rows.Err()
+}
+
+// AccountMonths lists the months of an account missing a statement
+type AccountMonths struct {
+	Institution   string
+	AccountNumber string
+	// Missing are the months, as "2006-01", without a statement ending in them between the first and the last statement
+	Missing []string
+}
+
+// MissingMonths returns the months missing a statement for every account in the store
+func (s *Store) MissingMonths() ([]AccountMonths, error) {
+	rows, err := s.db.Query(`SELECT i.code, a.number, s.period_end
+		FROM statements s JOIN accounts a ON a.id = s.account_id JOIN institutions i ON i.id = a.institution_id
+		ORDER BY i.code, a.number, s.period_end`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var accounts []AccountMonths
+	var months map[string]bool
+	var first, last time.Time
+
+	flush := func() {
+		if len(accounts) == 0 {
+			return
+		}
+		current := &accounts[len(accounts)-1]
+		for month := first; !month.After(last); month = month.AddDate(0, 1, 0) {
+			if !months[month.Format("2006-01")] {
+				current.Missing = append(current.Missing, month.Format("2006-01"))
+			}
+		}
+	}
+
+	for rows.Next() {
+		var institution, number, end string
+		if err := rows.Scan(&institution, &number, &end); err != nil {
+			return nil, err
+		}
+		periodEnd, err := time.Parse(dateLayout, end)
+		if err != nil {
+			return nil, err
+		}
+		month := time.Date(periodEnd.Year(), periodEnd.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+		if len(accounts) == 0 || accounts[len(accounts)-1].Institution != institution || accounts[len(accounts)-1].AccountNumber != number {
+			flush()
+			accounts = append(accounts, AccountMonths{Institution: institution, AccountNumber: number})
+			months = map[string]bool{}
+			first = month
+		}
+		months[month.Format("2006-01")] = true
+		last = month
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	flush()
+
+	return accounts, nil
+}
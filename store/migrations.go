@@ -0,0 +1,89 @@
+package store
+
+// migrations are applied in order, the schema version is the number of the migrations applied.
+// Never edit a released migration, append a new one instead.
+var migrations = []string{
+	// 1: institutions, accounts, statements and transactions
+	`
+CREATE TABLE institutions (
+	id   INTEGER PRIMARY KEY,
+	code TEXT NOT NULL UNIQUE
+);
+
+CREATE TABLE accounts (
+	id             INTEGER PRIMARY KEY,
+	institution_id INTEGER NOT NULL REFERENCES institutions(id),
+	account_type   TEXT NOT NULL,
+	number         TEXT NOT NULL,
+	UNIQUE (institution_id, number)
+);
+
+CREATE TABLE statements (
+	id                INTEGER PRIMARY KEY,
+	account_id        INTEGER NOT NULL REFERENCES accounts(id),
+	period_start      TEXT NOT NULL,
+	period_end        TEXT NOT NULL,
+	beginning_balance REAL NOT NULL,
+	ending_balance    REAL NOT NULL,
+	imported_at       TEXT NOT NULL,
+	UNIQUE (account_id, period_start, period_end)
+);
+
+CREATE TABLE transactions (
+	id               INTEGER PRIMARY KEY,
+	statement_id     INTEGER NOT NULL REFERENCES statements(id) ON DELETE CASCADE,
+	seq              INTEGER NOT NULL,
+	transaction_date TEXT,
+	posting_date     TEXT NOT NULL,
+	description      TEXT NOT NULL,
+	reference_number TEXT NOT NULL DEFAULT '',
+	account_number   TEXT NOT NULL DEFAULT '',
+	amount           REAL NOT NULL,
+	category         TEXT NOT NULL DEFAULT '',
+	UNIQUE (statement_id, seq)
+);
+
+CREATE INDEX transactions_posting_date ON transactions (posting_date);
+`,
+
+	// 2: import history of the source files
+	`
+CREATE TABLE imports (
+	id             INTEGER PRIMARY KEY,
+	path           TEXT NOT NULL,
+	sha256         TEXT NOT NULL,
+	format         TEXT NOT NULL DEFAULT '',
+	parser_version TEXT NOT NULL,
+	imported_at    TEXT NOT NULL,
+	status         TEXT NOT NULL,
+	error          TEXT NOT NULL DEFAULT '',
+	statements     INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE INDEX imports_sha256 ON imports (sha256);
+CREATE INDEX imports_path ON imports (path);
+`,
+	// 3: stable transaction ids
+	`
+ALTER TABLE transactions ADD COLUMN uid TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX transactions_uid ON transactions (uid);
+`,
+	// 4: spending categories
+	`
+ALTER TABLE transactions ADD COLUMN spending_category TEXT NOT NULL DEFAULT '';
+`,
+	// 5: transfers, the id of the other side of the transfer
+	`
+ALTER TABLE transactions ADD COLUMN transfer_id TEXT NOT NULL DEFAULT '';
+`,
+	// 6: payment type and counterparty split from the description
+	`
+ALTER TABLE transactions ADD COLUMN tx_type TEXT NOT NULL DEFAULT '';
+ALTER TABLE transactions ADD COLUMN counterparty TEXT NOT NULL DEFAULT '';
+`,
+	// 7: source of the spending category: manual, rules or model
+	`
+ALTER TABLE transactions ADD COLUMN category_source TEXT NOT NULL DEFAULT '';
+`,
+}
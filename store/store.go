@@ -0,0 +1,409 @@
+// package store provides the sqlite backed store of the statements, so the statements of the months can be imported
+// as they come and queried with sql. Importing a statement again replaces it instead of duplicating its transactions.
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+
+	"github.com/muly/bank-tx/model"
+	"github.com/muly/bank-tx/transfer"
+)
+
+const dateLayout = "2006-01-02"
+
+// Store is the sqlite database of the statements
+type Store struct {
+	db *sql.DB
+}
+
+// Open opens the sqlite database file, creating it when missing, and migrates it to the latest schema.
+// The transactions stored before the IDs were introduced get their IDs assigned.
+func Open(path string) (*Store, error) {
+	db, err := sql.Open("sqlite3", "file:"+path+"?_foreign_keys=on&_busy_timeout=5000")
+	if err != nil {
+		return nil, err
+	}
+	// sqlite allows a single writer, one connection avoids the busy errors
+	db.SetMaxOpenConns(1)
+
+	s := &Store{db: db}
+	if err := s.migrate(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to migrate %s: %v", path, err)
+	}
+	if err := s.assignIDs(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to assign the transaction ids of %s: %v", path, err)
+	}
+	return s, nil
+}
+
+// assignIDs stores the IDs of the transactions that have none, see model.TransactionID, the IDs are assigned per statement
+// the way ImportStatement does, so the methods updating the transactions by their ID find them
+func (s *Store) assignIDs() error {
+	rows, err := s.db.Query(`SELECT t.id, t.statement_id, a.number, t.uid, t.transaction_date, t.posting_date, t.description, t.amount
+		FROM transactions t JOIN statements s ON s.id = t.statement_id JOIN accounts a ON a.id = s.account_id
+		WHERE t.statement_id IN (SELECT statement_id FROM transactions WHERE uid = '')
+		ORDER BY t.statement_id, t.seq`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	var statements []model.Statement
+	var rowIDs [][]int64
+	var last int64
+	for rows.Next() {
+		var rowID, statementID int64
+		var t model.Transaction
+		var accountNumber, postingDate string
+		var transactionDate sql.NullString
+		if err := rows.Scan(&rowID, &statementID, &accountNumber, &t.ID, &transactionDate, &postingDate, &t.Description, &t.Amount); err != nil {
+			return err
+		}
+		if transactionDate.Valid {
+			if t.TransactionDate, err = time.Parse(dateLayout, transactionDate.String); err != nil {
+				return err
+			}
+		}
+		if t.PostingDate, err = time.Parse(dateLayout, postingDate); err != nil {
+			return err
+		}
+		if len(statements) == 0 || statementID != last {
+			statements = append(statements, model.Statement{AccountNumber: accountNumber})
+			rowIDs = append(rowIDs, nil)
+			last = statementID
+		}
+		i := len(statements) - 1
+		statements[i].Transactions = append(statements[i].Transactions, t)
+		rowIDs[i] = append(rowIDs[i], rowID)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if len(statements) == 0 {
+		return nil
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	for i := range statements {
+		statements[i].AssignIDs()
+		for k, t := range statements[i].Transactions {
+			if _, err := tx.Exec(`UPDATE transactions SET uid = ? WHERE id = ? AND uid = ''`, t.ID, rowIDs[i][k]); err != nil {
+				return err
+			}
+		}
+	}
+	return tx.Commit()
+}
+
+// Close closes the database
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// DB returns the database for the queries the store has no method for
+func (s *Store) DB() *sql.DB {
+	return s.db
+}
+
+// Version returns the schema version of the database
+func (s *Store) Version() (int, error) {
+	var version int
+	err := s.db.QueryRow(`SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&version)
+	return version, err
+}
+
+// migrate applies the migrations newer than the schema version of the database, each in its own transaction
+func (s *Store) migrate() error {
+	if _, err := s.db.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (version INTEGER PRIMARY KEY, applied_at TEXT NOT NULL)`); err != nil {
+		return err
+	}
+
+	version, err := s.Version()
+	if err != nil {
+		return err
+	}
+	if version > len(migrations) {
+		return fmt.Errorf("database schema version %d is newer than the supported version %d", version, len(migrations))
+	}
+
+	for i := version; i < len(migrations); i++ {
+		tx, err := s.db.Begin()
+		if err != nil {
+			return err
+		}
+		if _, err := tx.Exec(migrations[i]); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("migration %d: %v", i+1, err)
+		}
+		if _, err := tx.Exec(`INSERT INTO schema_migrations (version, applied_at) VALUES (?, ?)`, i+1, time.Now().UTC().Format(time.RFC3339)); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("migration %d: %v", i+1, err)
+		}
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("migration %d: %v", i+1, err)
+		}
+	}
+	return nil
+}
+
+// ImportResult tells what an import did to the statement
+type ImportResult struct {
+	StatementID int64
+	// Created is set when the statement was not in the store, otherwise its balances and transactions were replaced
+	Created      bool
+	Transactions int
+}
+
+// ImportStatement adds the statement to the store. A statement is identified by its institution, account and period,
+// so importing the same statement again replaces it and never duplicates its transactions.
+// The transactions without an ID get their stable ID assigned, and the spending categories of the replaced transactions
+// are kept for the transactions of the same ID that have none.
+func (s *Store) ImportStatement(statement model.Statement) (ImportResult, error) {
+	var result ImportResult
+
+	statement.Transactions = append([]model.Transaction(nil), statement.Transactions...)
+	statement.AssignIDs()
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return result, err
+	}
+	defer tx.Rollback()
+
+	accountID, err := upsertAccount(tx, statement)
+	if err != nil {
+		return result, err
+	}
+
+	var categories map[string]model.Transaction
+	start, end := statement.PeriodStartDate.Format(dateLayout), statement.PeriodEndDate.Format(dateLayout)
+	err = tx.QueryRow(`SELECT id FROM statements WHERE account_id = ? AND period_start = ? AND period_end = ?`,
+		accountID, start, end).Scan(&result.StatementID)
+	switch {
+	case err == sql.ErrNoRows:
+		result.Created = true
+		res, err := tx.Exec(`INSERT INTO statements (account_id, period_start, period_end, beginning_balance, ending_balance, imported_at)
+			VALUES (?, ?, ?, ?, ?, ?)`,
+			accountID, start, end, statement.BeginningBalance, statement.EndingBalance, time.Now().UTC().Format(time.RFC3339))
+		if err != nil {
+			return result, fmt.Errorf("failed to insert statement: %v", err)
+		}
+		if result.StatementID, err = res.LastInsertId(); err != nil {
+			return result, err
+		}
+	case err != nil:
+		return result, err
+	default:
+		if categories, err = spendingCategories(tx, result.StatementID); err != nil {
+			return result, err
+		}
+		if _, err := tx.Exec(`UPDATE statements SET beginning_balance = ?, ending_balance = ?, imported_at = ? WHERE id = ?`,
+			statement.BeginningBalance, statement.EndingBalance, time.Now().UTC().Format(time.RFC3339), result.StatementID); err != nil {
+			return result, fmt.Errorf("failed to update statement: %v", err)
+		}
+		if _, err := tx.Exec(`DELETE FROM transactions WHERE statement_id = ?`, result.StatementID); err != nil {
+			return result, fmt.Errorf("failed to delete transactions: %v", err)
+		}
+	}
+
+	insert, err := tx.Prepare(`INSERT INTO transactions (statement_id, seq, uid, transaction_date, posting_date, description,
+		reference_number, account_number, amount, category, spending_category, category_source, transfer_id, tx_type, counterparty)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return result, err
+	}
+	defer insert.Close()
+
+	for i, t := range statement.Transactions {
+		if t.SpendingCategory == "" {
+			t.SpendingCategory, t.CategorySource = categories[t.ID].SpendingCategory, categories[t.ID].CategorySource
+		}
+		if _, err := insert.Exec(result.StatementID, i, t.ID, nullDate(t.TransactionDate), t.PostingDate.Format(dateLayout), t.Description,
+			t.ReferenceNumber, t.AccountNumber, t.Amount, t.Category, t.SpendingCategory, t.CategorySource, t.TransferID, t.TxType, t.Counterparty); err != nil {
+			return result, fmt.Errorf("failed to insert transaction %q: %v", t.Description, err)
+		}
+		result.Transactions++
+	}
+
+	return result, tx.Commit()
+}
+
+// spendingCategories returns the spending categories of the transactions of the statement, with their sources, by their ID
+func spendingCategories(tx *sql.Tx, statementID int64) (map[string]model.Transaction, error) {
+	rows, err := tx.Query(`SELECT uid, spending_category, category_source FROM transactions WHERE statement_id = ? AND spending_category != ''`, statementID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	categories := map[string]model.Transaction{}
+	for rows.Next() {
+		var t model.Transaction
+		if err := rows.Scan(&t.ID, &t.SpendingCategory, &t.CategorySource); err != nil {
+			return nil, err
+		}
+		categories[t.ID] = t
+	}
+	return categories, rows.Err()
+}
+
+// SetSpendingCategory sets the spending category of the transactions of the ID along with its source, e.g. model.SourceManual,
+// an empty category clears it. It returns the number of the transactions updated.
+func (s *Store) SetSpendingCategory(id, category, source string) (int64, error) {
+	if category == "" {
+		source = ""
+	}
+	res, err := s.db.Exec(`UPDATE transactions SET spending_category = ?, category_source = ? WHERE uid = ?`, category, source, id)
+	if err != nil {
+		return 0, fmt.Errorf("failed to set the spending category of %s: %v", id, err)
+	}
+	return res.RowsAffected()
+}
+
+// MarkTransfers matches the transfers among all the statements of the store and stores the TransferID of both sides
+// of every pair, clearing the transfers no longer matched. It returns the transfers found.
+// Run it after the imports, as the other side of a transfer is often in a statement imported later.
+func (s *Store) MarkTransfers(c transfer.Config) ([]transfer.Pair, error) {
+	statements, err := s.Statements()
+	if err != nil {
+		return nil, err
+	}
+	for i := range statements {
+		for k := range statements[i].Transactions {
+			statements[i].Transactions[k].TransferID = ""
+		}
+	}
+	pairs, err := transfer.Mark(statements, c)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`UPDATE transactions SET transfer_id = '' WHERE transfer_id != ''`); err != nil {
+		return nil, fmt.Errorf("failed to clear the transfers: %v", err)
+	}
+	for _, p := range pairs {
+		for _, side := range [][2]string{{p.Out.Transaction.ID, p.In.Transaction.ID}, {p.In.Transaction.ID, p.Out.Transaction.ID}} {
+			if _, err := tx.Exec(`UPDATE transactions SET transfer_id = ? WHERE uid = ?`, side[1], side[0]); err != nil {
+				return nil, fmt.Errorf("failed to mark the transfer %s: %v", side[0], err)
+			}
+		}
+	}
+	return pairs, tx.Commit()
+}
+
+// upsertAccount returns the id of the statement account, adding the institution and the account when missing
+func upsertAccount(tx *sql.Tx, statement model.Statement) (int64, error) {
+	if _, err := tx.Exec(`INSERT INTO institutions (code) VALUES (?) ON CONFLICT (code) DO NOTHING`, statement.Institution); err != nil {
+		return 0, fmt.Errorf("failed to insert institution: %v", err)
+	}
+	var institutionID int64
+	if err := tx.QueryRow(`SELECT id FROM institutions WHERE code = ?`, statement.Institution).Scan(&institutionID); err != nil {
+		return 0, err
+	}
+
+	if _, err := tx.Exec(`INSERT INTO accounts (institution_id, account_type, number) VALUES (?, ?, ?)
+		ON CONFLICT (institution_id, number) DO UPDATE SET account_type = excluded.account_type`,
+		institutionID, statement.AccountType, statement.AccountNumber); err != nil {
+		return 0, fmt.Errorf("failed to insert account: %v", err)
+	}
+	var accountID int64
+	err := tx.QueryRow(`SELECT id FROM accounts WHERE institution_id = ? AND number = ?`, institutionID, statement.AccountNumber).Scan(&accountID)
+	return accountID, err
+}
+
+// Statements returns the statements of the store with their transactions, ordered by account and period.
+func (s *Store) Statements() ([]model.Statement, error) {
+	rows, err := s.db.Query(`SELECT s.id, i.code, a.account_type, a.number, s.period_start, s.period_end, s.beginning_balance, s.ending_balance
+		FROM statements s JOIN accounts a ON a.id = s.account_id JOIN institutions i ON i.id = a.institution_id
+		ORDER BY i.code, a.number, s.period_start`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var statements []model.Statement
+	index := map[int64]int{}
+	for rows.Next() {
+		var id int64
+		var statement model.Statement
+		var start, end string
+		if err := rows.Scan(&id, &statement.Institution, &statement.AccountType, &statement.AccountNumber, &start, &end,
+			&statement.BeginningBalance, &statement.EndingBalance); err != nil {
+			return nil, err
+		}
+		if statement.PeriodStartDate, err = time.Parse(dateLayout, start); err != nil {
+			return nil, err
+		}
+		if statement.PeriodEndDate, err = time.Parse(dateLayout, end); err != nil {
+			return nil, err
+		}
+		index[id] = len(statements)
+		statements = append(statements, statement)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	txRows, err := s.db.Query(`SELECT statement_id, uid, transaction_date, posting_date, description, reference_number, account_number, amount,
+		category, spending_category, category_source, transfer_id, tx_type, counterparty FROM transactions ORDER BY statement_id, seq`)
+	if err != nil {
+		return nil, err
+	}
+	defer txRows.Close()
+
+	for txRows.Next() {
+		var statementID int64
+		var t model.Transaction
+		var transactionDate sql.NullString
+		var postingDate string
+		if err := txRows.Scan(&statementID, &t.ID, &transactionDate, &postingDate, &t.Description, &t.ReferenceNumber, &t.AccountNumber,
+			&t.Amount, &t.Category, &t.SpendingCategory, &t.CategorySource, &t.TransferID, &t.TxType, &t.Counterparty); err != nil {
+			return nil, err
+		}
+		if transactionDate.Valid {
+			if t.TransactionDate, err = time.Parse(dateLayout, transactionDate.String); err != nil {
+				return nil, err
+			}
+		}
+		if t.PostingDate, err = time.Parse(dateLayout, postingDate); err != nil {
+			return nil, err
+		}
+		i, ok := index[statementID]
+		if !ok {
+			continue
+		}
+		statements[i].Transactions = append(statements[i].Transactions, t)
+	}
+	if err := txRows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range statements {
+		statements[i].AssignIDs()
+	}
+	return statements, nil
+}
+
+// nullDate stores the zero date as null
+func nullDate(t time.Time) interface{} {
+	if t.IsZero() {
+		return nil
+	}
+	return t.Format(dateLayout)
+}
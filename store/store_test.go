@@ -0,0 +1,299 @@
+package store
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/muly/bank-tx/model"
+	"github.com/muly/bank-tx/transfer"
+)
+
+func TestStatements(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bank-tx.db")
+	s, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	statement := model.Statement{
+		Institution:      model.BofA,
+		AccountType:      model.CreditCard,
+		AccountNumber:    "4400 1234 5678 1234",
+		PeriodStartDate:  time.Date(2024, 9, 12, 0, 0, 0, 0, time.UTC),
+		PeriodEndDate:    time.Date(2024, 10, 11, 0, 0, 0, 0, time.UTC),
+		BeginningBalance: 1905.57,
+		EndingBalance:    42.75,
+		Transactions: []model.Transaction{
+			{TransactionDate: time.Date(2024, 9, 28, 0, 0, 0, 0, time.UTC), PostingDate: time.Date(2024, 9, 30, 0, 0, 0, 0, time.UTC),
+				Description: "PAYMENT - THANK YOU", ReferenceNumber: "0027", AccountNumber: "1234", Amount: -1905.57, Category: "Payments and Other Credits",
+				TxType: "ACH", Counterparty: "TD BANK"},
+			{PostingDate: time.Date(2024, 10, 11, 0, 0, 0, 0, time.UTC), Description: "INTEREST CHARGED ON PURCHASES", Category: "Interest Charged"},
+			{TransactionDate: time.Date(2024, 9, 13, 0, 0, 0, 0, time.UTC), PostingDate: time.Date(2024, 9, 16, 0, 0, 0, 0, time.UTC),
+				Description: "ERERE RERE COUNTY SCHOOL", ReferenceNumber: "0881", AccountNumber: "1234", Amount: 42.75, Category: "Purchases and Adjustments"},
+		},
+	}
+	if _, err := s.ImportStatement(statement); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// reopening does not apply the migrations again
+	s, err = Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	version, err := s.Version()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if version != len(migrations) {
+		t.Errorf("expected schema version %d, got %d", len(migrations), version)
+	}
+
+	statements, err := s.Statements()
+	if err != nil {
+		t.Fatal(err)
+	}
+	statement.AssignIDs()
+	if len(statements) != 1 || !reflect.DeepEqual(statements[0], statement) {
+		t.Errorf("expected %+v, got %+v", statement, statements)
+	}
+}
+
+func TestImports(t *testing.T) {
+	s, err := Open(filepath.Join(t.TempDir(), "bank-tx.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	hash := Hash([]byte("statement"))
+	if _, err := s.RecordImport(Import{Path: "a.txt", SHA256: hash, Format: "td", ParserVersion: "1", Status: ImportOK, Statements: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.RecordImport(Import{Path: "b.txt", SHA256: Hash([]byte("other")), ParserVersion: "1", Status: ImportFailed, Error: "unknown statement format"}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		hash     string
+		version  string
+		expected bool
+	}{
+		{hash: hash, version: "1", expected: true},
+		{hash: hash, version: "2", expected: false},
+		{hash: Hash([]byte("other")), version: "1", expected: false},
+	}
+	for _, tt := range tests {
+		ok, err := s.ImportedOK(tt.hash, tt.version)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ok != tt.expected {
+			t.Errorf("ImportedOK(%s, %s): expected %v, got %v", tt.hash[:8], tt.version, tt.expected, ok)
+		}
+	}
+
+	last, err := s.LastImport("b.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if last == nil || last.Status != ImportFailed || last.Error != "unknown statement format" {
+		t.Errorf("unexpected last import %+v", last)
+	}
+	if last, err := s.LastImport("c.txt"); err != nil || last != nil {
+		t.Errorf("expected no import, got %+v, %v", last, err)
+	}
+}
+
+func TestMissingMonths(t *testing.T) {
+	s, err := Open(filepath.Join(t.TempDir(), "bank-tx.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	for _, period := range []struct{ account, start, end string }{
+		{"123-4567890", "2023-01-21", "2023-02-20"},
+		{"123-4567890", "2023-02-21", "2023-03-20"},
+		{"123-4567890", "2023-05-21", "2023-06-20"},
+		{"123-4567890", "2023-07-21", "2023-08-20"},
+		{"999-0000000", "2023-01-21", "2023-02-20"},
+	} {
+		start, _ := time.Parse(dateLayout, period.start)
+		end, _ := time.Parse(dateLayout, period.end)
+		if _, err := s.ImportStatement(model.Statement{Institution: model.TD, AccountType: model.Checking, AccountNumber: period.account,
+			PeriodStartDate: start, PeriodEndDate: end}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	accounts, err := s.MissingMonths()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []AccountMonths{
+		{Institution: model.TD, AccountNumber: "123-4567890", Missing: []string{"2023-04", "2023-05", "2023-07"}},
+		{Institution: model.TD, AccountNumber: "999-0000000"},
+	}
+	if !reflect.DeepEqual(accounts, expected) {
+		t.Errorf("expected %+v, got %+v", expected, accounts)
+	}
+}
+
+func TestSetSpendingCategory(t *testing.T) {
+	s, err := Open(filepath.Join(t.TempDir(), "bank-tx.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	statement := model.Statement{Institution: model.TD, AccountType: model.Checking, AccountNumber: "123-4567890",
+		PeriodStartDate: time.Date(2023, 3, 21, 0, 0, 0, 0, time.UTC), PeriodEndDate: time.Date(2023, 4, 20, 0, 0, 0, 0, time.UTC),
+		Transactions: []model.Transaction{
+			{PostingDate: time.Date(2023, 4, 3, 0, 0, 0, 0, time.UTC), Description: "COSTCO WHSE #1111", Amount: -120.50},
+		}}
+	statement.AssignIDs()
+	id := statement.Transactions[0].ID
+
+	if _, err := s.ImportStatement(statement); err != nil {
+		t.Fatal(err)
+	}
+	updated, err := s.SetSpendingCategory(id, "Food:Groceries", model.SourceManual)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated != 1 {
+		t.Errorf("expected 1 transaction updated, got %d", updated)
+	}
+
+	// the category survives the re-import of the statement
+	if _, err := s.ImportStatement(statement); err != nil {
+		t.Fatal(err)
+	}
+	statements, err := s.Statements()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := statements[0].Transactions[0]; got.SpendingCategory != "Food:Groceries" || got.CategorySource != model.SourceManual {
+		t.Errorf("expected the manual category Food:Groceries, got %q by %q", got.SpendingCategory, got.CategorySource)
+	}
+}
+
+func TestOpen_assignIDs(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bank-tx.db")
+	s, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	statement := model.Statement{Institution: model.TD, AccountType: model.Checking, AccountNumber: "123-4567890",
+		PeriodStartDate: time.Date(2023, 3, 21, 0, 0, 0, 0, time.UTC), PeriodEndDate: time.Date(2023, 4, 20, 0, 0, 0, 0, time.UTC),
+		Transactions: []model.Transaction{
+			{PostingDate: time.Date(2023, 4, 3, 0, 0, 0, 0, time.UTC), Description: "COSTCO WHSE #1111", Amount: -120.50},
+			{PostingDate: time.Date(2023, 4, 3, 0, 0, 0, 0, time.UTC), Description: "COSTCO WHSE #1111", Amount: -120.50},
+		}}
+	if _, err := s.ImportStatement(statement); err != nil {
+		t.Fatal(err)
+	}
+	// the transactions stored before the uid column was added
+	if _, err := s.DB().Exec(`UPDATE transactions SET uid = ''`); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err = Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	statement.AssignIDs()
+	for _, tx := range statement.Transactions {
+		updated, err := s.SetSpendingCategory(tx.ID, "Food:Groceries", model.SourceManual)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if updated != 1 {
+			t.Errorf("expected 1 transaction updated for %s, got %d", tx.ID, updated)
+		}
+	}
+}
+
+func TestMarkTransfers(t *testing.T) {
+	s, err := Open(filepath.Join(t.TempDir(), "bank-tx.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	checking := model.Statement{Institution: model.TD, AccountType: model.Checking, AccountNumber: "123-4567890",
+		PeriodStartDate: time.Date(2024, 9, 21, 0, 0, 0, 0, time.UTC), PeriodEndDate: time.Date(2024, 10, 20, 0, 0, 0, 0, time.UTC),
+		Transactions: []model.Transaction{
+			{PostingDate: time.Date(2024, 9, 27, 0, 0, 0, 0, time.UTC), Description: "TD BILL PAY SERV, BANK OF AMERICA ONLINE PMT", Amount: -1905.57},
+			{PostingDate: time.Date(2024, 10, 3, 0, 0, 0, 0, time.UTC), Description: "COSTCO WHSE #1111", Amount: -120.50},
+		}}
+	card := model.Statement{Institution: model.BofA, AccountType: model.CreditCard, AccountNumber: "4400 1234 5678 1234",
+		PeriodStartDate: time.Date(2024, 9, 12, 0, 0, 0, 0, time.UTC), PeriodEndDate: time.Date(2024, 10, 11, 0, 0, 0, 0, time.UTC),
+		Transactions: []model.Transaction{
+			{PostingDate: time.Date(2024, 9, 30, 0, 0, 0, 0, time.UTC), Description: "PAYMENT - THANK YOU", Amount: -1905.57},
+		}}
+	for _, statement := range []model.Statement{checking, card} {
+		if _, err := s.ImportStatement(statement); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	pairs, err := s.MarkTransfers(transfer.DefaultConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pairs) != 1 {
+		t.Fatalf("expected 1 transfer, got %+v", pairs)
+	}
+
+	// both sides of the transfer are stored
+	statements, err := s.Statements()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var marked []string
+	for _, statement := range statements {
+		for _, tx := range statement.Transactions {
+			if tx.TransferID != "" {
+				marked = append(marked, tx.Description)
+			}
+		}
+	}
+	expected := []string{"PAYMENT - THANK YOU", "TD BILL PAY SERV, BANK OF AMERICA ONLINE PMT"}
+	if !reflect.DeepEqual(marked, expected) {
+		t.Errorf("expected the transfers %v, got %v", expected, marked)
+	}
+	if out, in := statements[1].Transactions[0], statements[0].Transactions[0]; out.TransferID != in.ID || in.TransferID != out.ID {
+		t.Errorf("expected the sides to point to each other, got %+v and %+v", out, in)
+	}
+
+	// the transfer no longer matched is cleared
+	card.Transactions[0].Amount = -1000
+	if _, err := s.ImportStatement(card); err != nil {
+		t.Fatal(err)
+	}
+	if pairs, err = s.MarkTransfers(transfer.DefaultConfig()); err != nil {
+		t.Fatal(err)
+	}
+	var count int
+	if err := s.DB().QueryRow(`SELECT COUNT(*) FROM transactions WHERE transfer_id != ''`).Scan(&count); err != nil {
+		t.Fatal(err)
+	}
+	if len(pairs) != 0 || count != 0 {
+		t.Errorf("expected no transfers, got %d pairs and %d marked transactions", len(pairs), count)
+	}
+}
@@ -47,15 +47,67 @@ Subtotal: 17,537.72
 	}
 
 	// Output:
-	// {Category:Electronic Deposits PostingDate:2023-03-22 00:00:00 +0000 UTC Description:TD ZELLE RECEIVED, erer434ree r34rere re5rerer4344re Amount:418}
-	// {Category:Electronic Deposits PostingDate:2023-04-02 00:00:00 +0000 UTC Description:ACH DEPOSIT, rere erer ererereL Amount:6377.3}
-	// {Category:Electronic Deposits PostingDate:2023-04-08 00:00:00 +0000 UTC Description:ACH DEPOSIT, fererer  erereer dfdferr Amount:6377.3}
-	// {Category:Electronic Deposits PostingDate:2023-04-18 00:00:00 +0000 UTC Description:ACH DEPOSIT, rere rer4tr rtrtrrtr Amount:3745.84}
-	// {Category:Electronic Payments PostingDate:2023-03-28 00:00:00 +0000 UTC Description:ELECTRONIC PMT-WEB, BKOFAM CK WEBXFR TRANSFER ****234533 Amount:1000}
-	// {Category:Electronic Payments PostingDate:2023-03-28 00:00:00 +0000 UTC Description:TD BILL PAY SERV, BANK OF AMERICA ONLINE PMT TDB****34454454 Amount:4223.27}
-	// {Category:Electronic Payments PostingDate:2023-04-11 00:00:00 +0000 UTC Description:TD BILL PAY SERV, BANK OF AMERICA ONLINE PMT TDB****34454454 Amount:500}
-	// {Category:Electronic Payments PostingDate:2023-04-11 00:00:00 +0000 UTC Description:TD BILL PAY SERV, BANK OF AMERICA ONLINE PMT TDB****34454454 Amount:4313.34}
-	// {Category:Electronic Payments PostingDate:2023-04-11 00:00:00 +0000 UTC Description:ELECTRONIC PMT-WEB, EEERERERERE MTG PAYMENTS ****311244 Amount:6208.46}
-	// {Category:Electronic Payments PostingDate:2023-04-12 00:00:00 +0000 UTC Description:ELECTRONIC PMT-WEB, ERERERE CARD RER PAYMNT ****63101563793 Amount:292.65}
-	// {Category:Electronic Payments PostingDate:2023-04-12 00:00:00 +0000 UTC Description:ELECTRONIC PMT-WEB, REREREER CK WEBXFR TRANSFER ****062167 Amount:1000}
+	// {Category:Electronic Deposits PostingDate:2023-03-22 00:00:00 +0000 UTC Description:TD ZELLE RECEIVED, erer434ree r34rere re5rerer4344re Amount:418 TxType:Zelle Counterparty:erer434ree r34rere re5rerer4344re Reference:}
+	// {Category:Electronic Deposits PostingDate:2023-04-02 00:00:00 +0000 UTC Description:ACH DEPOSIT, rere erer ererereL Amount:6377.3 TxType:ACH Counterparty:rere erer ererereL Reference:}
+	// {Category:Electronic Deposits PostingDate:2023-04-08 00:00:00 +0000 UTC Description:ACH DEPOSIT, fererer  erereer dfdferr Amount:6377.3 TxType:ACH Counterparty:fererer  erereer dfdferr Reference:}
+	// {Category:Electronic Deposits PostingDate:2023-04-18 00:00:00 +0000 UTC Description:ACH DEPOSIT, rere rer4tr rtrtrrtr Amount:3745.84 TxType:ACH Counterparty:rere rer4tr rtrtrrtr Reference:}
+	// {Category:Electronic Payments PostingDate:2023-03-28 00:00:00 +0000 UTC Description:ELECTRONIC PMT-WEB, BKOFAM CK WEBXFR TRANSFER ****234533 Amount:1000 TxType:WebPayment Counterparty:BKOFAM CK WEBXFR TRANSFER Reference:****234533}
+	// {Category:Electronic Payments PostingDate:2023-03-28 00:00:00 +0000 UTC Description:TD BILL PAY SERV, BANK OF AMERICA ONLINE PMT TDB****34454454 Amount:4223.27 TxType:BillPay Counterparty:BANK OF AMERICA ONLINE PMT Reference:TDB****34454454}
+	// {Category:Electronic Payments PostingDate:2023-04-11 00:00:00 +0000 UTC Description:TD BILL PAY SERV, BANK OF AMERICA ONLINE PMT TDB****34454454 Amount:500 TxType:BillPay Counterparty:BANK OF AMERICA ONLINE PMT Reference:TDB****34454454}
+	// {Category:Electronic Payments PostingDate:2023-04-11 00:00:00 +0000 UTC Description:TD BILL PAY SERV, BANK OF AMERICA ONLINE PMT TDB****34454454 Amount:4313.34 TxType:BillPay Counterparty:BANK OF AMERICA ONLINE PMT Reference:TDB****34454454}
+	// {Category:Electronic Payments PostingDate:2023-04-11 00:00:00 +0000 UTC Description:ELECTRONIC PMT-WEB, EEERERERERE MTG PAYMENTS ****311244 Amount:6208.46 TxType:WebPayment Counterparty:EEERERERERE MTG PAYMENTS Reference:****311244}
+	// {Category:Electronic Payments PostingDate:2023-04-12 00:00:00 +0000 UTC Description:ELECTRONIC PMT-WEB, ERERERE CARD RER PAYMNT ****63101563793 Amount:292.65 TxType:WebPayment Counterparty:ERERERE CARD RER PAYMNT Reference:****63101563793}
+	// {Category:Electronic Payments PostingDate:2023-04-12 00:00:00 +0000 UTC Description:ELECTRONIC PMT-WEB, REREREER CK WEBXFR TRANSFER ****062167 Amount:1000 TxType:WebPayment Counterparty:REREREER CK WEBXFR TRANSFER Reference:****062167}
+}
+
+func ExampleStatement_ToModel() {
+	s, err := ParseStatement(`
+Statement Period: Mar 21 2023-Apr 20 2023
+Account # 123-4567890
+Beginning Balance 1,000.00
+Ending Balance 1,418.00
+Electronic Deposits
+03/22 TD ZELLE RECEIVED, erer434ree r34rere re5rerer4344re 918.00
+Subtotal: 918.00
+Electronic Payments
+04/11 TD BILL PAY SERV, BANK OF AMERICA ONLINE PMT TDB****34454454 500.00
+Subtotal: 500.00
+`)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	m := s.ToModel()
+	fmt.Println(m.Institution, m.AccountType, m.AccountNumber)
+	for _, tx := range m.Transactions {
+		fmt.Printf("%s %s %.2f %s %q %q\n", tx.PostingDate.Format("2006-01-02"), tx.Category, tx.Amount, tx.TxType, tx.Counterparty, tx.ReferenceNumber)
+	}
+	fmt.Printf("%.2f\n", m.BeginningBalance+m.Total())
+
+	// Output:
+	// td checking 123-4567890
+	// 2023-03-22 Electronic Deposits 918.00 Zelle "erer434ree r34rere re5rerer4344re" ""
+	// 2023-04-11 Electronic Payments -500.00 BillPay "BANK OF AMERICA ONLINE PMT" "TDB****34454454"
+	// 1418.00
+}
+
+func ExampleParseDescription() {
+	for _, description := range []string{
+		"TD ZELLE RECEIVED, JANE DOE",
+		"TD BILL PAY SERV, BANK OF AMERICA ONLINE PMT TDB****34454454",
+		"ELECTRONIC PMT-WEB, EEERERERERE MTG PAYMENTS ****311244",
+		"ACH DEPOSIT, ACME CORP PAYROLL",
+		"CHECK # 1024",
+	} {
+		txType, counterparty, reference := ParseDescription(description)
+		fmt.Printf("%q %q %q\n", txType, counterparty, reference)
+	}
+
+	// Output:
+	// "Zelle" "JANE DOE" ""
+	// "BillPay" "BANK OF AMERICA ONLINE PMT" "TDB****34454454"
+	// "WebPayment" "EEERERERERE MTG PAYMENTS" "****311244"
+	// "ACH" "ACME CORP PAYROLL" ""
+	// "" "CHECK # 1024" ""
 }
@@ -3,23 +3,48 @@
 package td
 
 import (
-	"encoding/csv"
 	"fmt"
-	"os"
 	"regexp"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/muly/bank-tx/csvexport"
+	"github.com/muly/bank-tx/model"
 	"github.com/muly/bank-tx/util"
 )
 
-// Transaction struct to hold transaction data
+// Transaction types, the kind of the transfer in front of the description
+const (
+	ACH        = "ACH"
+	Zelle      = "Zelle"
+	BillPay    = "BillPay"
+	WebPayment = "WebPayment"
+)
+
+// descriptionTypes maps the description prefixes to the transaction types
+var descriptionTypes = []struct {
+	prefix string
+	txType string
+}{
+	{"ACH DEPOSIT,", ACH},
+	{"ACH DEBIT,", ACH},
+	{"TD ZELLE RECEIVED,", Zelle},
+	{"TD ZELLE SENT,", Zelle},
+	{"TD BILL PAY SERV,", BillPay},
+	{"ELECTRONIC PMT-WEB,", WebPayment},
+}
+
+// Transaction struct to hold transaction data.
+// TxType, Counterparty and Reference are split from the description, see ParseDescription.
 type Transaction struct {
-	Category    string
-	PostingDate time.Time
-	Description string
-	Amount      float64
+	Category     string
+	PostingDate  time.Time
+	Description  string
+	Amount       float64
+	TxType       string
+	Counterparty string
+	Reference    string
 }
 
 // Statement struct to hold overall statement info
@@ -99,6 +124,7 @@ func ParseStatement(data string) (*Statement, error) {
 				Description: description,
 				Amount:      amount,
 			}
+			transaction.TxType, transaction.Counterparty, transaction.Reference = ParseDescription(description)
 			statement.Transactions = append(statement.Transactions, transaction)
 			continue
 		}
@@ -133,43 +159,73 @@ func ParseStatement(data string) (*Statement, error) {
 		}
 	}
 	calculatedEndingBalance := util.RoundToOneDecimal(statement.BeginningBalance + deposits - payments)
-	statement.EndingBalance = util.RoundToOneDecimal(statement.EndingBalance)
 
-	if calculatedEndingBalance != statement.EndingBalance {
+	if calculatedEndingBalance != util.RoundToOneDecimal(statement.EndingBalance) {
 		return nil, fmt.Errorf("ending balance mismatch: expected %.2f, got %.2f", calculatedEndingBalance, statement.EndingBalance)
 	}
 
 	return &statement, nil
 }
 
-// SaveTransactionsToCSV saves the transactions to a CSV file
-func SaveTransactionsToCSV(statement *Statement) error {
-	filename := fmt.Sprintf("%s|%s_to_%s.csv", statement.AccountNumber,
-		statement.PeriodStartDate.Format("Jan_02_2006"),
-		statement.PeriodEndDate.Format("Jan_02_2006"))
+// ParseDescription splits the description into the transaction type, the counterparty and the masked reference,
+// e.g. "TD BILL PAY SERV, BANK OF AMERICA ONLINE PMT TDB****34454454" is a BillPay to "BANK OF AMERICA ONLINE PMT"
+// with the reference "TDB****34454454". The descriptions of the unknown types are returned as the counterparty.
+func ParseDescription(description string) (txType, counterparty, reference string) {
+	counterparty = strings.TrimSpace(description)
+	for _, t := range descriptionTypes {
+		if strings.HasPrefix(counterparty, t.prefix) {
+			txType = t.txType
+			counterparty = strings.TrimSpace(counterparty[len(t.prefix):])
+			break
+		}
+	}
 
-	file, err := os.Create(filename)
-	if err != nil {
-		return err
+	if i := strings.LastIndex(counterparty, " "); i >= 0 && strings.Contains(counterparty[i+1:], "**") {
+		reference = counterparty[i+1:]
+		counterparty = strings.TrimSpace(counterparty[:i])
 	}
-	defer file.Close()
+	return txType, counterparty, reference
+}
 
-	writer := csv.NewWriter(file)
-	defer writer.Flush()
+// ToModel converts the statement to the bank neutral model, the payments are negated as td prints all the amounts as positive
+func (s Statement) ToModel() model.Statement {
+	transactions := make([]model.Transaction, 0, len(s.Transactions))
+	for _, tx := range s.Transactions {
+		amount := tx.Amount
+		if tx.Category == "Electronic Payments" {
+			amount = -amount
+		}
+		transactions = append(transactions, model.Transaction{
+			TransactionDate: tx.PostingDate,
+			PostingDate:     tx.PostingDate,
+			Description:     tx.Description,
+			Amount:          amount,
+			Category:        tx.Category,
+			ReferenceNumber: tx.Reference,
+			TxType:          tx.TxType,
+			Counterparty:    tx.Counterparty,
+		})
+	}
 
-	// Write transaction headers
-	writer.Write([]string{"Category", "Posting Date", "Description", "Amount"})
+	return model.Statement{
+		Institution:      model.TD,
+		AccountType:      model.Checking,
+		AccountNumber:    s.AccountNumber,
+		PeriodStartDate:  s.PeriodStartDate,
+		PeriodEndDate:    s.PeriodEndDate,
+		BeginningBalance: s.BeginningBalance,
+		EndingBalance:    s.EndingBalance,
+		Transactions:     transactions,
+	}
+}
 
-	// Write each transaction
-	for _, t := range statement.Transactions {
-		writer.Write([]string{
-			t.Category,
-			t.PostingDate.Format("01/02/2006"),
-			t.Description,
-			fmt.Sprintf("%.2f", t.Amount),
-		})
+// SaveTransactionsToCSV saves the transactions to a csv file in the current directory, named by csvexport.DefaultNameTemplate
+func SaveTransactionsToCSV(statement *Statement) error {
+	paths, err := csvexport.WriteFiles(".", csvexport.DefaultNameTemplate, []model.Statement{statement.ToModel()}, csvexport.Options{})
+	if err != nil {
+		return err
 	}
 
-	fmt.Printf("Transactions saved to %s\n", filename)
+	fmt.Printf("Transactions saved to %s\n", paths[0])
 	return nil
 }
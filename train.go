@@ -0,0 +1,108 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/muly/bank-tx/categorize"
+	"github.com/muly/bank-tx/classify"
+	"github.com/muly/bank-tx/store"
+)
+
+// runTrain trains the classifier on the transactions of the store categorized by hand, reports its cross validation accuracy
+// and saves it
+func runTrain(args []string) error {
+	flags := flag.NewFlagSet("train", flag.ExitOnError)
+	db := flags.String("db", "bank-tx.db", "sqlite database file")
+	output := flags.String("model", "model.json", "file the model is saved to")
+	folds := flags.Int("folds", 5, "number of the cross validation folds")
+	flags.Parse(args)
+
+	s, err := store.Open(*db)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+
+	statements, err := s.Statements()
+	if err != nil {
+		return err
+	}
+
+	examples := classify.Examples(statements)
+	if len(examples) == 0 {
+		return fmt.Errorf("no transactions categorized by hand in %s, see categorize -id", *db)
+	}
+
+	accuracy, err := classify.CrossValidate(examples, *folds)
+	if err != nil {
+		fmt.Println("Warning:", err)
+	} else {
+		fmt.Printf("%d-fold cross validation accuracy: %.1f%%\n", *folds, accuracy*100)
+	}
+
+	m := classify.Train(examples)
+	if err := m.Save(*output); err != nil {
+		return err
+	}
+	fmt.Printf("model trained on %d transactions in %d categories saved to %s\n", len(examples), len(m.Examples), *output)
+	return nil
+}
+
+// runSuggest prints the suggested category of the uncategorized transactions in the store by the model,
+// falling back to the rules file, and saves them with -apply
+func runSuggest(args []string) error {
+	flags := flag.NewFlagSet("suggest", flag.ExitOnError)
+	db := flags.String("db", "bank-tx.db", "sqlite database file")
+	input := flags.String("model", "model.json", "model file saved by train")
+	rules := flags.String("rules", "", "yaml or json rules file used when the model is not confident")
+	minConfidence := flags.Float64("min-confidence", 0.6, "confidence the model suggestion needs to be used")
+	apply := flags.Bool("apply", false, "save the suggested categories")
+	flags.Parse(args)
+
+	m, err := classify.LoadModel(*input)
+	if err != nil {
+		return err
+	}
+	c := classify.Categorizer{Model: m, MinConfidence: *minConfidence}
+	if *rules != "" {
+		if c.Rules, err = categorize.Load(*rules); err != nil {
+			return err
+		}
+	}
+
+	s, err := store.Open(*db)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+
+	statements, err := s.Statements()
+	if err != nil {
+		return err
+	}
+
+	suggested := 0
+	for _, statement := range statements {
+		for _, tx := range statement.Transactions {
+			if tx.SpendingCategory != "" || tx.TransferID != "" {
+				continue
+			}
+			suggestion, ok := c.Suggest(statement, tx)
+			if !ok {
+				continue
+			}
+			suggested++
+			fmt.Printf("%s %s %s %.2f => %s (%s, %.0f%%)\n", tx.ID, tx.PostingDate.Format("2006-01-02"), tx.Description, tx.Amount,
+				suggestion.Category, suggestion.Source, suggestion.Confidence*100)
+			if !*apply {
+				continue
+			}
+			if _, err := s.SetSpendingCategory(tx.ID, suggestion.Category, suggestion.Source); err != nil {
+				return err
+			}
+		}
+	}
+	fmt.Printf("%d transactions suggested\n", suggested)
+	return nil
+}
@@ -0,0 +1,62 @@
+package transfer
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/muly/bank-tx/model"
+	"github.com/muly/bank-tx/td"
+)
+
+func ExampleMark() {
+	data, err := os.ReadFile("../td/sample.txt")
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	checking, err := td.ParseStatement(string(data))
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	date := func(month time.Month, day int) time.Time {
+		return time.Date(2023, month, day, 0, 0, 0, 0, time.UTC)
+	}
+	card := model.Statement{
+		Institution:   model.BofA,
+		AccountType:   model.CreditCard,
+		AccountNumber: "4400 1234 5678 1234",
+		Transactions: []model.Transaction{
+			{PostingDate: date(3, 30), Description: "PAYMENT - THANK YOU", Amount: -4223.27},
+			{PostingDate: date(4, 12), Description: "AMAZON MKTPLACE PMTS", Amount: 500},
+			{PostingDate: date(4, 13), Description: "PAYMENT - THANK YOU", Amount: -4313.34},
+			{PostingDate: date(4, 13), Description: "PAYMENT - THANK YOU", Amount: -500},
+		},
+	}
+
+	statements := []model.Statement{checking.ToModel(), card}
+	pairs, err := Mark(statements, DefaultConfig())
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	for _, p := range pairs {
+		fmt.Printf("%s: %s %s %.2f -> %s %s %.2f\n", p.Rule,
+			p.Out.Transaction.PostingDate.Format("2006-01-02"), p.Out.Transaction.Description, p.Out.Transaction.Amount,
+			p.In.Transaction.PostingDate.Format("2006-01-02"), p.In.Transaction.Description, p.In.Transaction.Amount)
+	}
+	for _, tx := range statements[1].Transactions {
+		fmt.Println(tx.Description, tx.Amount, "transfer:", tx.TransferID != "")
+	}
+
+	// Output:
+	// bofa card payment: 2023-03-28 TD BILL PAY SERV, BANK OF AMERICA ONLINE PMT TDB****34454454 -4223.27 -> 2023-03-30 PAYMENT - THANK YOU -4223.27
+	// bofa card payment: 2023-04-11 TD BILL PAY SERV, BANK OF AMERICA ONLINE PMT TDB****34454454 -500.00 -> 2023-04-13 PAYMENT - THANK YOU -500.00
+	// bofa card payment: 2023-04-11 TD BILL PAY SERV, BANK OF AMERICA ONLINE PMT TDB****34454454 -4313.34 -> 2023-04-13 PAYMENT - THANK YOU -4313.34
+	// PAYMENT - THANK YOU -4223.27 transfer: true
+	// AMAZON MKTPLACE PMTS 500 transfer: false
+	// PAYMENT - THANK YOU -4313.34 transfer: true
+	// PAYMENT - THANK YOU -500 transfer: true
+}
@@ -0,0 +1,184 @@
+// package transfer provides the functions to find the transfers between the accounts, like the credit card payments
+// from the checking account, and to mark them so they are left out of the spending and income totals
+package transfer
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+
+	"github.com/muly/bank-tx/model"
+	"github.com/muly/bank-tx/util"
+)
+
+// Rule pairs the outflows with the inflows by the payee patterns of their descriptions, both are case insensitive regexps
+type Rule struct {
+	Name string `yaml:"name" json:"name"`
+	// From matches the description of the money leaving the account, e.g. "TD BILL PAY SERV, BANK OF AMERICA ONLINE PMT"
+	From string `yaml:"from" json:"from"`
+	// To matches the description of the money coming into the other account, e.g. "PAYMENT - THANK YOU"
+	To string `yaml:"to" json:"to"`
+}
+
+// Config of the transfer matching
+type Config struct {
+	// MaxDays is the number of days between the outflow and the inflow of a transfer
+	MaxDays int    `yaml:"max_days" json:"max_days"`
+	Rules   []Rule `yaml:"rules" json:"rules"`
+}
+
+// DefaultConfig returns the config matching the td bill payments with the bofa credit card payments
+func DefaultConfig() Config {
+	return Config{
+		MaxDays: 5,
+		Rules: []Rule{
+			{Name: "bofa card payment", From: `BANK OF AMERICA|BOFA`, To: `PAYMENT - THANK YOU`},
+			{Name: "card payment", From: `CARD ?(SERVICES|PMT|PAYMENT)|CRCARDPMT|AUTOPAY|EPAY`, To: `PAYMENT|THANK YOU`},
+			{Name: "transfer", From: `TRANSFER|XFER`, To: `TRANSFER|XFER`},
+		},
+	}
+}
+
+// LoadConfig loads the yaml or json config file, see transfers.example.yaml, the missing fields are taken from DefaultConfig
+func LoadConfig(filename string) (Config, error) {
+	c := DefaultConfig()
+	if err := util.DecodeFile(filename, &c); err != nil {
+		return c, err
+	}
+	return c, nil
+}
+
+// Ref locates a transaction, Statement and Index are the indexes in the statements given and in their transactions
+type Ref struct {
+	Statement   int
+	Index       int
+	Transaction model.Transaction
+}
+
+// Pair is a transfer, the money leaving an account (Out) and coming into another one (In)
+type Pair struct {
+	Rule string
+	Out  Ref
+	In   Ref
+	// Days is the number of days from the outflow to the inflow, negative when the inflow posted first
+	Days int
+}
+
+type compiledRule struct {
+	name     string
+	from, to *regexp.Regexp
+}
+
+// Match pairs the outflows with the inflows of the same amount on the other accounts, posted within MaxDays of each other
+// and matching the payee patterns of a rule. Every transaction is in at most one pair, the closest dates are paired first.
+func Match(statements []model.Statement, c Config) ([]Pair, error) {
+	rules := make([]compiledRule, 0, len(c.Rules))
+	for _, r := range c.Rules {
+		from, err := regexp.Compile("(?i)" + r.From)
+		if err != nil {
+			return nil, fmt.Errorf("rule %q: invalid from pattern: %v", r.Name, err)
+		}
+		to, err := regexp.Compile("(?i)" + r.To)
+		if err != nil {
+			return nil, fmt.Errorf("rule %q: invalid to pattern: %v", r.Name, err)
+		}
+		rules = append(rules, compiledRule{name: r.Name, from: from, to: to})
+	}
+
+	var outs, ins []Ref
+	for i, s := range statements {
+		for k, tx := range s.Transactions {
+			ref := Ref{Statement: i, Index: k, Transaction: tx}
+			switch flow := s.Flow(tx); {
+			case flow < 0:
+				outs = append(outs, ref)
+			case flow > 0:
+				ins = append(ins, ref)
+			}
+		}
+	}
+
+	// every candidate pair, then the closest ones are taken first
+	var candidates []Pair
+	for _, out := range outs {
+		for _, in := range ins {
+			if sameAccount(statements[out.Statement], statements[in.Statement]) {
+				continue
+			}
+			if util.RoundToTwoDecimal(math.Abs(out.Transaction.Amount)) != util.RoundToTwoDecimal(math.Abs(in.Transaction.Amount)) {
+				continue
+			}
+			days := int(math.Round(in.Transaction.PostingDate.Sub(out.Transaction.PostingDate).Hours() / 24))
+			if days > c.MaxDays || days < -c.MaxDays {
+				continue
+			}
+			for _, r := range rules {
+				if r.from.MatchString(out.Transaction.Description) && r.to.MatchString(in.Transaction.Description) {
+					candidates = append(candidates, Pair{Rule: r.name, Out: out, In: in, Days: days})
+					break
+				}
+			}
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return closer(candidates[i], candidates[j])
+	})
+
+	used := map[[2]int]bool{}
+	var pairs []Pair
+	for _, p := range candidates {
+		outKey, inKey := [2]int{p.Out.Statement, p.Out.Index}, [2]int{p.In.Statement, p.In.Index}
+		if used[outKey] || used[inKey] {
+			continue
+		}
+		used[outKey], used[inKey] = true, true
+		pairs = append(pairs, p)
+	}
+	return pairs, nil
+}
+
+// Mark matches the transfers and sets the TransferID of both sides of every pair, the transactions are updated in place.
+// The transactions without an ID get their stable ID assigned first.
+func Mark(statements []model.Statement, c Config) ([]Pair, error) {
+	for i := range statements {
+		statements[i].AssignIDs()
+	}
+
+	pairs, err := Match(statements, c)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range pairs {
+		statements[p.Out.Statement].Transactions[p.Out.Index].TransferID = p.In.Transaction.ID
+		statements[p.In.Statement].Transactions[p.In.Index].TransferID = p.Out.Transaction.ID
+	}
+	return pairs, nil
+}
+
+func sameAccount(a, b model.Statement) bool {
+	return a.Institution == b.Institution && a.AccountNumber == b.AccountNumber
+}
+
+// closer orders the candidate pairs by the distance of their dates, the inflows after the outflows first,
+// then by the statement order for a stable result
+func closer(a, b Pair) bool {
+	if abs(a.Days) != abs(b.Days) {
+		return abs(a.Days) < abs(b.Days)
+	}
+	if (a.Days >= 0) != (b.Days >= 0) {
+		return a.Days >= 0
+	}
+	if a.Out.Statement != b.Out.Statement || a.Out.Index != b.Out.Index {
+		return a.Out.Statement < b.Out.Statement || (a.Out.Statement == b.Out.Statement && a.Out.Index < b.Out.Index)
+	}
+	return a.In.Statement < b.In.Statement || (a.In.Statement == b.In.Statement && a.In.Index < b.In.Index)
+}
+
+func abs(i int) int {
+	if i < 0 {
+		return -i
+	}
+	return i
+}
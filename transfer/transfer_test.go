@@ -0,0 +1,116 @@
+package transfer
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/muly/bank-tx/model"
+)
+
+func TestMatch(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2023, 4, d, 0, 0, 0, 0, time.UTC) }
+	checking := model.Statement{Institution: model.TD, AccountType: model.Checking, AccountNumber: "123-4567890",
+		Transactions: []model.Transaction{
+			{PostingDate: day(11), Description: "TD BILL PAY SERV, BANK OF AMERICA ONLINE PMT", Amount: -500},
+			{PostingDate: day(12), Description: "ACH TRANSFER FROM SAVINGS", Amount: 500},
+		}}
+
+	tests := []struct {
+		name     string
+		other    model.Statement
+		config   Config
+		expected int
+	}{
+		{
+			name: "payment",
+			other: model.Statement{Institution: model.BofA, AccountType: model.CreditCard, AccountNumber: "1234",
+				Transactions: []model.Transaction{{PostingDate: day(13), Description: "PAYMENT - THANK YOU", Amount: -500}}},
+			config:   DefaultConfig(),
+			expected: 1,
+		},
+		{
+			name: "outside the date window",
+			other: model.Statement{Institution: model.BofA, AccountType: model.CreditCard, AccountNumber: "1234",
+				Transactions: []model.Transaction{{PostingDate: day(20), Description: "PAYMENT - THANK YOU", Amount: -500}}},
+			config:   DefaultConfig(),
+			expected: 0,
+		},
+		{
+			name: "purchase is not a payment",
+			other: model.Statement{Institution: model.BofA, AccountType: model.CreditCard, AccountNumber: "1234",
+				Transactions: []model.Transaction{{PostingDate: day(13), Description: "PAYMENT - THANK YOU", Amount: 500}}},
+			config:   DefaultConfig(),
+			expected: 0,
+		},
+		{
+			name: "no matching rule",
+			other: model.Statement{Institution: model.BofA, AccountType: model.CreditCard, AccountNumber: "1234",
+				Transactions: []model.Transaction{{PostingDate: day(13), Description: "PAYMENT - THANK YOU", Amount: -500}}},
+			config:   Config{MaxDays: 5, Rules: []Rule{{Name: "chase", From: "CHASE", To: "PAYMENT"}}},
+			expected: 0,
+		},
+		{
+			name: "savings transfer",
+			other: model.Statement{Institution: model.TD, AccountType: model.Checking, AccountNumber: "999-0000000",
+				Transactions: []model.Transaction{{PostingDate: day(12), Description: "ONLINE XFER TO CHECKING", Amount: -500}}},
+			config:   DefaultConfig(),
+			expected: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pairs, err := Match([]model.Statement{checking, tt.other}, tt.config)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(pairs) != tt.expected {
+				t.Errorf("expected %d pairs, got %+v", tt.expected, pairs)
+			}
+		})
+	}
+
+	if _, err := Match(nil, Config{Rules: []Rule{{Name: "invalid", From: "(", To: "x"}}}); err == nil {
+		t.Error("expected an error for the invalid pattern")
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		file     string
+		content  string
+		expected Config
+	}{
+		{"transfers.yaml", "rules:\n  - name: savings\n    from: TO SAV\n    to: FROM CHK\n",
+			Config{MaxDays: 5, Rules: []Rule{{Name: "savings", From: "TO SAV", To: "FROM CHK"}}}},
+		{"transfers.json", `{"max_days": 3}`, Config{MaxDays: 3, Rules: DefaultConfig().Rules}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			path := filepath.Join(dir, tt.file)
+			if err := os.WriteFile(path, []byte(tt.content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			c, err := LoadConfig(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(c, tt.expected) {
+				t.Errorf("expected %+v, got %+v", tt.expected, c)
+			}
+		})
+	}
+
+	c, err := LoadConfig("transfers.example.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Match(nil, c); err != nil {
+		t.Errorf("invalid example config: %v", err)
+	}
+}
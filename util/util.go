@@ -1,10 +1,16 @@
 package util
 
 import (
+	"encoding/json"
+	"fmt"
 	"math"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
 )
 
 // RoundToOneDecimal rounds a float64 to 1 decimal places
@@ -26,7 +32,45 @@ func LoadFileData(filename string) (string, error) {
 	return string(content), nil
 }
 
+// DecodeFile decodes the json file, by its .json extension, or the yaml file into v
+func DecodeFile(filename string, v interface{}) error {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+
+	if strings.EqualFold(filepath.Ext(filename), ".json") {
+		err = json.Unmarshal(data, v)
+	} else {
+		err = yaml.Unmarshal(data, v)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to parse %s: %v", filename, err)
+	}
+	return nil
+}
+
 // ParseFloat parses float values
 func ParseFloat(s string) (float64, error) {
 	return strconv.ParseFloat(strings.ReplaceAll(strings.ReplaceAll(s, "$", ""), ",", ""), 64)
 }
+
+// AddYearToDate adds the correct year to a month-day date, formatted per layout, based on the statement period
+func AddYearToDate(dateStr, layout string, startPeriod, endPeriod time.Time) (time.Time, error) {
+	parsedDate, err := time.Parse(layout, dateStr)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	// Set the year based on start and end periods
+	year := endPeriod.Year()
+
+	// For cross-year periods, dates after the end month belong to startPeriod's year
+	if startPeriod.Year() != endPeriod.Year() {
+		if parsedDate.Month() > endPeriod.Month() {
+			year = startPeriod.Year()
+		}
+	}
+
+	return time.Date(year, parsedDate.Month(), parsedDate.Day(), 0, 0, 0, 0, time.UTC), nil
+}
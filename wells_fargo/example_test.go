@@ -0,0 +1,45 @@
+package wells_fargo
+
+import (
+	"fmt"
+)
+
+func ExampleParseStatement() {
+	statement := `
+Wells Fargo Everyday Checking
+January 12, 2024
+Account number: 1234567890
+
+Statement period activity summary
+Beginning balance on 12/15 $2,500.00
+Deposits/Additions 3,418.00
+Withdrawals/Subtractions - 1,963.55
+Ending balance on 1/12 $3,954.45
+
+Transaction history
+Date   Check Number  Description                            Deposits/Credits   Withdrawals/Debits    Ending daily balance
+12/18                Purchase authorized on 12/16 Costco Whse #1111 Town ST                145.67
+12/18                Zelle From Some Name on 12/18 Ref # Pp0Abcdefg   418.00                                     2,772.33
+12/22                Employer Inc Payroll 231222 Some Name          3,000.00                                     5,772.33
+12/28  1001          Check                                                               1,200.00
+12/28                Bank of America Online Pmt 231228 Some Name                           617.88                3,954.45
+Ending balance on 1/12 3,954.45
+Totals $3,418.00 $1,963.55
+	`
+
+	s, err := ParseStatement(statement)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	for _, tx := range s.Transactions {
+		fmt.Printf("%+v\n", tx)
+	}
+
+	// Output:
+	// {PostingDate:2023-12-18 00:00:00 +0000 UTC CheckNumber: Description:Purchase authorized on 12/16 Costco Whse #1111 Town ST Amount:-145.67 Category:Withdrawals/Debits DailyBalance:0 EndOfDay:false}
+	// {PostingDate:2023-12-18 00:00:00 +0000 UTC CheckNumber: Description:Zelle From Some Name on 12/18 Ref # Pp0Abcdefg Amount:418 Category:Deposits/Credits DailyBalance:2772.33 EndOfDay:true}
+	// {PostingDate:2023-12-22 00:00:00 +0000 UTC CheckNumber: Description:Employer Inc Payroll 231222 Some Name Amount:3000 Category:Deposits/Credits DailyBalance:5772.33 EndOfDay:true}
+	// {PostingDate:2023-12-28 00:00:00 +0000 UTC CheckNumber:1001 Description:Check Amount:-1200 Category:Withdrawals/Debits DailyBalance:0 EndOfDay:false}
+	// {PostingDate:2023-12-28 00:00:00 +0000 UTC CheckNumber: Description:Bank of America Online Pmt 231228 Some Name Amount:-617.88 Category:Withdrawals/Debits DailyBalance:3954.45 EndOfDay:true}
+}
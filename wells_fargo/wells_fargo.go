@@ -0,0 +1,423 @@
+// package wells_fargo provides the parsing functions to process the wells fargo checking account statements
+package wells_fargo
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/muly/bank-tx/model"
+	"github.com/muly/bank-tx/util"
+)
+
+const (
+	Deposits    = "Deposits/Credits"
+	Withdrawals = "Withdrawals/Debits"
+	dailyColumn = "Ending daily balance"
+)
+
+// maxRowsPerDay caps the rows of a day whose columns are resolved by trying every deposit/withdrawal combination
+const maxRowsPerDay = 16
+
+// Transaction struct to hold transaction data
+type Transaction struct {
+	PostingDate time.Time
+	CheckNumber string
+	Description string
+	// Amount is positive for deposits and negative for withdrawals
+	Amount   float64
+	Category string
+	// DailyBalance is the ending daily balance, it is printed on the last transaction of the day only
+	DailyBalance float64
+	EndOfDay     bool
+}
+
+// Statement struct to hold overall statement info
+type Statement struct {
+	AccountNumber    string
+	PeriodStartDate  time.Time
+	PeriodEndDate    time.Time
+	BeginningBalance float64
+	EndingBalance    float64
+	Transactions     []Transaction
+}
+
+var (
+	reStatementDate = regexp.MustCompile(`^[A-Z][a-z]+ \d{1,2}, \d{4}$`)
+	reAccount       = regexp.MustCompile(`^Account number: (\d+)$`)
+	reBalance       = regexp.MustCompile(`^(Beginning|Ending) balance on (\d{1,2}/\d{1,2}) (\$?[\d,]+\.\d{2})$`)
+	reSummary       = regexp.MustCompile(`^(Deposits/Additions|Withdrawals/Subtractions) (-?\s?\$?[\d,]+\.\d{2})$`)
+	reTotals        = regexp.MustCompile(`^Totals (\$?[\d,]+\.\d{2}) (\$?[\d,]+\.\d{2})$`)
+	reTransaction   = regexp.MustCompile(`^(\d{1,2}/\d{1,2})\s+(?:(\d{3,})\s+)?(.+?)((?:\s+[\d,]+\.\d{2}){1,2})$`)
+	reAmount        = regexp.MustCompile(`[\d,]+\.\d{2}`)
+)
+
+// row is a transaction line before its amounts are assigned to the columns
+type row struct {
+	date        string
+	checkNumber string
+	description string
+	// amounts are the trailing amounts along with the position of their last character in the line
+	amounts []float64
+	ends    []int
+}
+
+// ParseStatement parses the input data into a Statement struct
+func ParseStatement(data string) (*Statement, error) {
+	var statement Statement
+	var statementDate time.Time
+	var beginDate, endDate string
+	var summaryDeposits, summaryWithdrawals float64
+	var totalDeposits, totalWithdrawals float64
+	var columns map[string]int
+	var rows []row
+	var err error
+	hasTotals := false
+
+	lines := strings.Split(data, "\n")
+
+	for _, raw := range lines {
+		// positions matter for the amount columns, so only the trailing space is trimmed
+		raw = strings.TrimRight(raw, " \t\r")
+		line := strings.TrimSpace(raw)
+
+		if line == "" {
+			continue
+		}
+
+		if reStatementDate.MatchString(line) {
+			statementDate, err = time.Parse("January 2, 2006", line)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse statement date: %v", err)
+			}
+			continue
+		}
+
+		if match := reAccount.FindStringSubmatch(line); match != nil {
+			statement.AccountNumber = match[1]
+			continue
+		}
+
+		// Parse beginning and ending balances, the ending balance is repeated below the transaction history
+		if match := reBalance.FindStringSubmatch(line); match != nil {
+			balance, err := util.ParseFloat(match[3])
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse %s balance: %s, error: %v", strings.ToLower(match[1]), match[3], err)
+			}
+			if match[1] == "Beginning" {
+				beginDate = match[2]
+				statement.BeginningBalance = balance
+			} else {
+				endDate = match[2]
+				statement.EndingBalance = balance
+			}
+			continue
+		}
+
+		if match := reSummary.FindStringSubmatch(line); match != nil {
+			amount, err := util.ParseFloat(strings.ReplaceAll(match[2], " ", ""))
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse %s: %s, error: %v", match[1], match[2], err)
+			}
+			if match[1] == "Deposits/Additions" {
+				summaryDeposits = amount
+			} else {
+				summaryWithdrawals = math.Abs(amount)
+			}
+			continue
+		}
+
+		if match := reTotals.FindStringSubmatch(line); match != nil {
+			if totalDeposits, err = util.ParseFloat(match[1]); err != nil {
+				return nil, fmt.Errorf("failed to parse deposits total: %s, error: %v", match[1], err)
+			}
+			if totalWithdrawals, err = util.ParseFloat(match[2]); err != nil {
+				return nil, fmt.Errorf("failed to parse withdrawals total: %s, error: %v", match[2], err)
+			}
+			hasTotals = true
+			continue
+		}
+
+		// The header gives the position of the amount columns
+		if strings.HasPrefix(line, "Date") && strings.Contains(line, Deposits) {
+			columns = parseColumns(raw)
+			continue
+		}
+
+		if match := reTransaction.FindStringSubmatchIndex(line); match != nil {
+			r := row{
+				date:        line[match[2]:match[3]],
+				description: line[match[6]:match[7]],
+			}
+			if match[4] >= 0 {
+				r.checkNumber = line[match[4]:match[5]]
+			}
+			// locate the trailing amounts in the raw line
+			offset := len(raw) - len(line)
+			for _, loc := range reAmount.FindAllStringIndex(raw[offset+match[8]:], -1) {
+				start, end := offset+match[8]+loc[0], offset+match[8]+loc[1]
+				amount, err := util.ParseFloat(raw[start:end])
+				if err != nil {
+					return nil, fmt.Errorf("failed to parse amount: %s, error: %v", raw[start:end], err)
+				}
+				r.amounts = append(r.amounts, amount)
+				r.ends = append(r.ends, end)
+			}
+			rows = append(rows, r)
+			continue
+		}
+
+		log.Printf("unprocessed line: %v", line)
+	}
+
+	statement.PeriodStartDate, statement.PeriodEndDate, err = parseStatementPeriod(beginDate, endDate, statementDate)
+	if err != nil {
+		return nil, err
+	}
+
+	// Use the text position of the amounts when the layout is preserved, otherwise work it out from the daily balances
+	if columns != nil && hasLayout(rows, columns) {
+		statement.Transactions, err = assignByPosition(rows, columns, statement.PeriodStartDate, statement.PeriodEndDate)
+	} else {
+		statement.Transactions, err = assignByBalance(rows, statement.BeginningBalance, statement.PeriodStartDate, statement.PeriodEndDate)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Validate the ending daily balances
+	balance := statement.BeginningBalance
+	deposits, withdrawals := 0.0, 0.0
+	for _, t := range statement.Transactions {
+		balance = util.RoundToTwoDecimal(balance + t.Amount)
+		if t.Amount > 0 {
+			deposits += t.Amount
+		} else {
+			withdrawals -= t.Amount
+		}
+		if t.EndOfDay && balance != util.RoundToTwoDecimal(t.DailyBalance) {
+			return nil, fmt.Errorf("ending daily balance mismatch on %s: expected %.2f, got %.2f", t.PostingDate.Format("01/02/2006"), t.DailyBalance, balance)
+		}
+	}
+
+	if balance != util.RoundToTwoDecimal(statement.EndingBalance) {
+		return nil, fmt.Errorf("ending balance mismatch: expected %.2f, got %.2f", statement.EndingBalance, balance)
+	}
+	if util.RoundToTwoDecimal(deposits) != util.RoundToTwoDecimal(summaryDeposits) {
+		return nil, fmt.Errorf("deposits mismatch: expected %.2f, got %.2f", summaryDeposits, deposits)
+	}
+	if util.RoundToTwoDecimal(withdrawals) != util.RoundToTwoDecimal(summaryWithdrawals) {
+		return nil, fmt.Errorf("withdrawals mismatch: expected %.2f, got %.2f", summaryWithdrawals, withdrawals)
+	}
+	if hasTotals && (util.RoundToTwoDecimal(totalDeposits) != util.RoundToTwoDecimal(deposits) ||
+		util.RoundToTwoDecimal(totalWithdrawals) != util.RoundToTwoDecimal(withdrawals)) {
+		return nil, fmt.Errorf("totals mismatch: expected %.2f/%.2f, got %.2f/%.2f", totalDeposits, totalWithdrawals, deposits, withdrawals)
+	}
+
+	return &statement, nil
+}
+
+// ToModel converts the statement to the bank neutral model
+func (s Statement) ToModel() model.Statement {
+	transactions := make([]model.Transaction, 0, len(s.Transactions))
+	for _, tx := range s.Transactions {
+		transactions = append(transactions, model.Transaction{
+			TransactionDate: tx.PostingDate,
+			PostingDate:     tx.PostingDate,
+			Description:     tx.Description,
+			ReferenceNumber: tx.CheckNumber,
+			Amount:          tx.Amount,
+			Category:        tx.Category,
+		})
+	}
+
+	return model.Statement{
+		Institution:      model.WellsFargo,
+		AccountType:      model.Checking,
+		AccountNumber:    s.AccountNumber,
+		PeriodStartDate:  s.PeriodStartDate,
+		PeriodEndDate:    s.PeriodEndDate,
+		BeginningBalance: s.BeginningBalance,
+		EndingBalance:    s.EndingBalance,
+		Transactions:     transactions,
+	}
+}
+
+// Helper functions
+
+// parseColumns returns the position of the right edge of the amount columns, as the amounts are right aligned
+func parseColumns(header string) map[string]int {
+	columns := map[string]int{}
+	for _, name := range []string{Deposits, Withdrawals, dailyColumn} {
+		if i := strings.Index(header, name); i >= 0 {
+			columns[name] = i + len(name)
+		}
+	}
+	if len(columns) != 3 {
+		return nil
+	}
+	return columns
+}
+
+// hasLayout reports whether the amounts are aligned with the header columns, pdf to text conversions often collapse the spaces
+func hasLayout(rows []row, columns map[string]int) bool {
+	for _, r := range rows {
+		for _, end := range r.ends {
+			d := columns[nearestColumn(columns, end)] - end
+			if d < -2 || d > 2 {
+				return false
+			}
+		}
+	}
+	return len(rows) > 0
+}
+
+// nearestColumn returns the column whose right edge is the closest to the given position
+func nearestColumn(columns map[string]int, end int) string {
+	nearest, distance := "", math.MaxInt
+	for _, name := range []string{Deposits, Withdrawals, dailyColumn} {
+		d := columns[name] - end
+		if d < 0 {
+			d = -d
+		}
+		if d < distance {
+			nearest, distance = name, d
+		}
+	}
+	return nearest
+}
+
+func assignByPosition(rows []row, columns map[string]int, startPeriod, endPeriod time.Time) ([]Transaction, error) {
+	transactions := make([]Transaction, 0, len(rows))
+	for _, r := range rows {
+		t, err := newTransaction(r, startPeriod, endPeriod)
+		if err != nil {
+			return nil, err
+		}
+		assigned := false
+		for i, amount := range r.amounts {
+			switch nearestColumn(columns, r.ends[i]) {
+			case Deposits:
+				t.Amount, t.Category = amount, Deposits
+			case Withdrawals:
+				t.Amount, t.Category = -amount, Withdrawals
+			case dailyColumn:
+				t.DailyBalance, t.EndOfDay = amount, true
+				continue
+			}
+			if assigned {
+				return nil, fmt.Errorf("more than one amount on %s %q", r.date, r.description)
+			}
+			assigned = true
+		}
+		if !assigned {
+			return nil, fmt.Errorf("no deposit or withdrawal amount on %s %q", r.date, r.description)
+		}
+		transactions = append(transactions, *t)
+	}
+	return transactions, nil
+}
+
+// assignByBalance finds the deposit/withdrawal combination of each day that adds up to its ending daily balance.
+// When more than one combination adds up, e.g. a deposit and a withdrawal of the same amount, the day is ambiguous
+// and only the column positions of the text layout can tell them apart.
+func assignByBalance(rows []row, beginBalance float64, startPeriod, endPeriod time.Time) ([]Transaction, error) {
+	transactions := make([]Transaction, 0, len(rows))
+	balance := beginBalance
+	var day []Transaction
+
+	for _, r := range rows {
+		t, err := newTransaction(r, startPeriod, endPeriod)
+		if err != nil {
+			return nil, err
+		}
+		t.Amount = r.amounts[0]
+		if len(r.amounts) == 2 {
+			t.DailyBalance, t.EndOfDay = r.amounts[1], true
+		}
+		day = append(day, *t)
+		if !t.EndOfDay {
+			continue
+		}
+
+		if len(day) > maxRowsPerDay {
+			return nil, fmt.Errorf("too many transactions on %s to work out the columns", r.date)
+		}
+		found := -1
+		for signs := 0; signs < 1<<len(day); signs++ {
+			total := balance
+			for i, d := range day {
+				if signs&(1<<i) != 0 {
+					total -= d.Amount
+				} else {
+					total += d.Amount
+				}
+			}
+			if util.RoundToTwoDecimal(total) != util.RoundToTwoDecimal(t.DailyBalance) {
+				continue
+			}
+			if found >= 0 {
+				return nil, fmt.Errorf("ambiguous deposits and withdrawals on %s, the amounts add up either way without the column layout", r.date)
+			}
+			found = signs
+		}
+		if found < 0 {
+			return nil, fmt.Errorf("ending daily balance mismatch on %s: no combination of the amounts adds up to %.2f", r.date, t.DailyBalance)
+		}
+		for i := range day {
+			if found&(1<<i) != 0 {
+				day[i].Amount, day[i].Category = -day[i].Amount, Withdrawals
+			} else {
+				day[i].Category = Deposits
+			}
+		}
+
+		transactions = append(transactions, day...)
+		balance = t.DailyBalance
+		day = nil
+	}
+
+	if len(day) > 0 {
+		return nil, fmt.Errorf("missing ending daily balance for the transactions on %s", day[0].PostingDate.Format("01/02/2006"))
+	}
+	return transactions, nil
+}
+
+func newTransaction(r row, startPeriod, endPeriod time.Time) (*Transaction, error) {
+	postingDate, err := util.AddYearToDate(r.date, "1/2", startPeriod, endPeriod)
+	if err != nil {
+		return nil, fmt.Errorf("error adding year to posting date: %v", err)
+	}
+	return &Transaction{
+		PostingDate: postingDate,
+		CheckNumber: r.checkNumber,
+		Description: r.description,
+	}, nil
+}
+
+// parseStatementPeriod builds the period from the month/day of the beginning and ending balances and the statement date
+func parseStatementPeriod(begin, end string, statementDate time.Time) (time.Time, time.Time, error) {
+	if begin == "" || end == "" || statementDate.IsZero() {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid statement period format")
+	}
+	endDate, err := time.Parse("1/2", end)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("failed to parse end period: %v", err)
+	}
+	beginDate, err := time.Parse("1/2", begin)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("failed to parse start period: %v", err)
+	}
+
+	endPeriod := time.Date(statementDate.Year(), endDate.Month(), endDate.Day(), 0, 0, 0, 0, time.UTC)
+	startYear := endPeriod.Year()
+	if beginDate.Month() > endDate.Month() {
+		startYear--
+	}
+	startPeriod := time.Date(startYear, beginDate.Month(), beginDate.Day(), 0, 0, 0, 0, time.UTC)
+
+	return startPeriod, endPeriod, nil
+}
@@ -0,0 +1,93 @@
+package wells_fargo
+
+import (
+	"os"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func loadSample(t *testing.T) string {
+	data, err := os.ReadFile("sample.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+// collapse removes the column alignment like the pdf to text conversions without the layout option do
+func collapse(data string) string {
+	return regexp.MustCompile(` {2,}`).ReplaceAllString(data, " ")
+}
+
+func TestParseStatement_layout(t *testing.T) {
+	data := loadSample(t)
+	date := func(month time.Month, day int) time.Time { return time.Date(2023, month, day, 0, 0, 0, 0, time.UTC) }
+	want := []Transaction{
+		{PostingDate: date(12, 18), Description: "Purchase authorized on 12/16 Costco Whse #1111 Town ST", Amount: -145.67, Category: Withdrawals},
+		{PostingDate: date(12, 18), Description: "Zelle From Some Name on 12/18 Ref # Pp0Abcdefg", Amount: 418, Category: Deposits, DailyBalance: 2772.33, EndOfDay: true},
+		{PostingDate: date(12, 22), Description: "Employer Inc Payroll 231222 Some Name", Amount: 3000, Category: Deposits, DailyBalance: 5772.33, EndOfDay: true},
+		{PostingDate: date(12, 28), CheckNumber: "1001", Description: "Check", Amount: -1200, Category: Withdrawals},
+		{PostingDate: date(12, 28), Description: "Bank of America Online Pmt 231228 Some Name", Amount: -617.88, Category: Withdrawals, DailyBalance: 3954.45, EndOfDay: true},
+	}
+
+	positional, err := ParseStatement(data)
+	if err != nil {
+		t.Fatalf("ParseStatement() error = %v", err)
+	}
+	if !reflect.DeepEqual(positional.Transactions, want) {
+		t.Errorf("ParseStatement() by position = %+v, want %+v", positional.Transactions, want)
+	}
+
+	balance, err := ParseStatement(collapse(data))
+	if err != nil {
+		t.Fatalf("ParseStatement() collapsed layout error = %v", err)
+	}
+	if !reflect.DeepEqual(balance, positional) {
+		t.Errorf("ParseStatement() by balance = %+v, want %+v", balance, positional)
+	}
+
+	// without the daily balance of the last day only the column positions tell the withdrawals apart
+	noDaily := strings.Replace(data, "617.88                3,954.45", "617.88", 1)
+	if noDaily == data {
+		t.Fatal("sample.txt is not aligned with the header columns")
+	}
+	got, err := ParseStatement(noDaily)
+	if err != nil {
+		t.Fatalf("ParseStatement() by position without the daily balance error = %v", err)
+	}
+	if got.Transactions[4].Amount != -617.88 || got.Transactions[4].Category != Withdrawals {
+		t.Errorf("ParseStatement() by position = %+v, want the withdrawal of 617.88", got.Transactions[4])
+	}
+	if _, err := ParseStatement(collapse(noDaily)); err == nil || !strings.Contains(err.Error(), "missing ending daily balance") {
+		t.Errorf("ParseStatement() by balance error = %v, want the missing ending daily balance", err)
+	}
+}
+
+func TestParseStatement_sameAmountDepositAndWithdrawal(t *testing.T) {
+	// a 50.00 refund and a 50.00 purchase on the same day add up to the daily balance with either sign
+	data := strings.Replace(loadSample(t),
+		"12/22                Employer Inc Payroll 231222 Some Name          3,000.00                                     5,772.33",
+		"12/22                Employer Inc Payroll 231222 Some Name          3,000.00\n"+
+			"12/22                Refund Some Store                                 50.00\n"+
+			"12/22                Purchase Some Store                                                    50.00                5,772.33", 1)
+	data = strings.Replace(data, "Deposits/Additions 3,418.00", "Deposits/Additions 3,468.00", 1)
+	data = strings.Replace(data, "Withdrawals/Subtractions - 1,963.55", "Withdrawals/Subtractions - 2,013.55", 1)
+	data = strings.Replace(data, "Totals $3,418.00 $1,963.55", "Totals $3,468.00 $2,013.55", 1)
+
+	s, err := ParseStatement(data)
+	if err != nil {
+		t.Fatalf("ParseStatement() by position error = %v", err)
+	}
+	refund, purchase := s.Transactions[3], s.Transactions[4]
+	if refund.Amount != 50 || refund.Category != Deposits || purchase.Amount != -50 || purchase.Category != Withdrawals {
+		t.Errorf("ParseStatement() by position = %+v and %+v, want the deposit then the withdrawal of 50.00", refund, purchase)
+	}
+
+	// without the column layout the rows cannot be told apart
+	if _, err := ParseStatement(collapse(data)); err == nil || !strings.Contains(err.Error(), "ambiguous deposits and withdrawals on 12/22") {
+		t.Errorf("ParseStatement() by balance error = %v, want the ambiguous deposits and withdrawals", err)
+	}
+}
@@ -0,0 +1,57 @@
+package xlsx
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+
+	"github.com/muly/bank-tx/bofa_cc"
+	"github.com/muly/bank-tx/model"
+)
+
+const bofaStatement = `
+Account# 4400 1234 5678 1234
+September 12 - October 11, 2024
+Previous Balance $1,905.57
+Payments and Other Credits -$1,905.57
+Purchases and Adjustments $42.75
+Fees Charged $0.00
+Interest Charged $0.00
+New Balance Total $42.75
+Payments and Other Credits
+09/28 09/30 PAYMENT - THANK YOU 0027 1234 -1,905.57
+Purchases and Adjustments
+09/13 09/16 ERERE RERE COUNTY SCHOOL FDFDDF-DDFDFD DF 0881 1234 42.75
+`
+
+func ExampleWriteWorkbook() {
+	s, err := bofa_cc.ParseStatement(bofaStatement)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := WriteWorkbook(&buf, []model.Statement{s.ToModel()}); err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	r, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	for _, f := range r.File {
+		fmt.Println(f.Name)
+	}
+
+	// Output:
+	// [Content_Types].xml
+	// _rels/.rels
+	// xl/workbook.xml
+	// xl/_rels/workbook.xml.rels
+	// xl/styles.xml
+	// xl/worksheets/sheet1.xml
+	// xl/worksheets/sheet2.xml
+}
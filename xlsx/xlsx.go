@@ -0,0 +1,299 @@
+// package xlsx provides the functions to export the statements as an excel workbook, with a summary sheet and
+// one sheet per statement. The workbook is written with the standard library only, as the minimal set of
+// office open xml parts excel, libreoffice and google sheets need.
+package xlsx
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/muly/bank-tx/model"
+	"github.com/muly/bank-tx/util"
+)
+
+// maxSheetName is the excel limit of the sheet name length
+const maxSheetName = 31
+
+// cell styles, the index of the cellXfs in styles.xml
+const (
+	styleDefault = iota
+	styleDate
+	styleCurrency
+	styleHeader
+)
+
+// cell is a value of a row, a string, a heading, a float64 amount or a time.Time date
+type cell interface{}
+
+// heading is a string cell of a header row
+type heading string
+
+// headings returns the header row of the names
+func headings(names ...string) []cell {
+	row := make([]cell, 0, len(names))
+	for _, name := range names {
+		row = append(row, heading(name))
+	}
+	return row
+}
+
+type sheet struct {
+	name   string
+	widths []int
+	rows   [][]cell
+}
+
+// WriteWorkbook writes the statements as a xlsx workbook. The first sheet summarizes the statements, their balances,
+// validation status and section totals, and every statement gets a sheet with its transactions.
+// The amounts are currency cells, the dates are date cells and the header rows are frozen.
+func WriteWorkbook(w io.Writer, statements []model.Statement) error {
+	sheets := []sheet{summarySheet(statements)}
+	names := map[string]bool{sheets[0].name: true}
+	for _, s := range statements {
+		sh := statementSheet(s)
+		sh.name = uniqueName(sh.name, names)
+		sheets = append(sheets, sh)
+	}
+
+	z := zip.NewWriter(w)
+	parts := []struct {
+		name    string
+		content string
+	}{
+		{"[Content_Types].xml", contentTypes(len(sheets))},
+		{"_rels/.rels", rootRels},
+		{"xl/workbook.xml", workbook(sheets)},
+		{"xl/_rels/workbook.xml.rels", workbookRels(len(sheets))},
+		{"xl/styles.xml", styles},
+	}
+	for i, sh := range sheets {
+		parts = append(parts, struct {
+			name    string
+			content string
+		}{fmt.Sprintf("xl/worksheets/sheet%d.xml", i+1), worksheet(sh)})
+	}
+
+	for _, part := range parts {
+		f, err := z.Create(part.name)
+		if err != nil {
+			return fmt.Errorf("failed to create %s: %v", part.name, err)
+		}
+		if _, err := io.WriteString(f, part.content); err != nil {
+			return fmt.Errorf("failed to write %s: %v", part.name, err)
+		}
+	}
+	return z.Close()
+}
+
+// summarySheet has a row per statement followed by the section totals of the statements
+func summarySheet(statements []model.Statement) sheet {
+	sh := sheet{
+		name:   "Summary",
+		widths: []int{14, 14, 24, 13, 13, 18, 14, 16, 40},
+		rows: [][]cell{
+			headings("Institution", "Account Type", "Account", "Period Start", "Period End", "Beginning Balance", "Total", "Ending Balance", "Validation"),
+		},
+	}
+	for _, s := range statements {
+		sh.rows = append(sh.rows, []cell{s.Institution, s.AccountType, s.AccountNumber, s.PeriodStartDate, s.PeriodEndDate,
+			s.BeginningBalance, util.RoundToTwoDecimal(s.Total()), s.EndingBalance, validationStatus(s.Validate())})
+	}
+
+	sh.rows = append(sh.rows, nil, headings("Account", "Period End", "Section", "Total"))
+	for _, s := range statements {
+		totals := map[string]float64{}
+		var sections []string
+		for _, tx := range s.Transactions {
+			section := tx.Category
+			if section == "" {
+				section = "Uncategorized"
+			}
+			if _, ok := totals[section]; !ok {
+				sections = append(sections, section)
+			}
+			totals[section] += tx.Amount
+		}
+		for _, section := range sections {
+			sh.rows = append(sh.rows, []cell{s.AccountNumber, s.PeriodEndDate, section, util.RoundToTwoDecimal(totals[section])})
+		}
+	}
+	return sh
+}
+
+// statementSheet has the transactions of the statement, named by the institution, the account ending and the period end
+func statementSheet(s model.Statement) sheet {
+	account := strings.ReplaceAll(s.AccountNumber, " ", "")
+	if len(account) > 4 {
+		account = account[len(account)-4:]
+	}
+
+	sh := sheet{
+		name:   fmt.Sprintf("%s %s %s", s.Institution, account, s.PeriodEndDate.Format("2006-01-02")),
+		widths: []int{16, 13, 50, 16, 10, 28, 14},
+		rows: [][]cell{
+			headings("Transaction Date", "Posting Date", "Description", "Reference", "Account", "Category", "Amount"),
+		},
+	}
+	for _, tx := range s.Transactions {
+		sh.rows = append(sh.rows, []cell{tx.TransactionDate, tx.PostingDate, tx.Description, tx.ReferenceNumber, tx.AccountNumber, tx.Category, tx.Amount})
+	}
+	return sh
+}
+
+// validationStatus is "OK" or the failed checks
+func validationStatus(v model.Validation) string {
+	if v.Valid {
+		return "OK"
+	}
+	var failed []string
+	for _, check := range v.Checks {
+		if !check.Passed {
+			failed = append(failed, fmt.Sprintf("%s: expected %.2f, got %.2f", check.Name, check.Expected, check.Actual))
+		}
+	}
+	return strings.Join(failed, "; ")
+}
+
+// uniqueName removes the characters excel does not allow in the sheet names and makes the name unique in the workbook
+func uniqueName(name string, names map[string]bool) string {
+	name = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`[]:*?/\`, r) {
+			return '_'
+		}
+		return r
+	}, name)
+	if len(name) > maxSheetName {
+		name = name[:maxSheetName]
+	}
+
+	unique := name
+	for i := 2; names[strings.ToLower(unique)]; i++ {
+		suffix := fmt.Sprintf(" (%d)", i)
+		if len(name)+len(suffix) > maxSheetName {
+			unique = name[:maxSheetName-len(suffix)] + suffix
+		} else {
+			unique = name + suffix
+		}
+	}
+	names[strings.ToLower(unique)] = true
+	return unique
+}
+
+func worksheet(sh sheet) string {
+	var b strings.Builder
+	b.WriteString(xml.Header)
+	b.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">`)
+	b.WriteString(`<sheetViews><sheetView workbookViewId="0"><pane ySplit="1" topLeftCell="A2" activePane="bottomLeft" state="frozen"/></sheetView></sheetViews>`)
+	b.WriteString(`<cols>`)
+	for i, width := range sh.widths {
+		fmt.Fprintf(&b, `<col min="%d" max="%d" width="%d" customWidth="1"/>`, i+1, i+1, width)
+	}
+	b.WriteString(`</cols><sheetData>`)
+	for r, row := range sh.rows {
+		fmt.Fprintf(&b, `<row r="%d">`, r+1)
+		for c, value := range row {
+			ref := fmt.Sprintf("%s%d", columnName(c), r+1)
+			switch v := value.(type) {
+			case string:
+				fmt.Fprintf(&b, `<c r="%s" s="%d" t="inlineStr"><is><t xml:space="preserve">%s</t></is></c>`, ref, styleDefault, escape(v))
+			case heading:
+				fmt.Fprintf(&b, `<c r="%s" s="%d" t="inlineStr"><is><t>%s</t></is></c>`, ref, styleHeader, escape(string(v)))
+			case float64:
+				fmt.Fprintf(&b, `<c r="%s" s="%d"><v>%s</v></c>`, ref, styleCurrency, strconv.FormatFloat(util.RoundToTwoDecimal(v)+0, 'f', -1, 64))
+			case time.Time:
+				if v.IsZero() {
+					continue
+				}
+				fmt.Fprintf(&b, `<c r="%s" s="%d"><v>%d</v></c>`, ref, styleDate, serialDate(v))
+			}
+		}
+		b.WriteString(`</row>`)
+	}
+	b.WriteString(`</sheetData></worksheet>`)
+	return b.String()
+}
+
+// serialDate is the excel date, the number of days since 1899-12-30
+func serialDate(t time.Time) int {
+	epoch := time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+	date := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	return int(date.Sub(epoch).Hours() / 24)
+}
+
+// columnName is the letter name of the zero based column index, e.g. "A", "Z", "AA"
+func columnName(i int) string {
+	name := ""
+	for i++; i > 0; i = (i - 1) / 26 {
+		name = string(rune('A'+(i-1)%26)) + name
+	}
+	return name
+}
+
+func escape(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+func contentTypes(sheets int) string {
+	var b strings.Builder
+	b.WriteString(xml.Header)
+	b.WriteString(`<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">`)
+	b.WriteString(`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>`)
+	b.WriteString(`<Default Extension="xml" ContentType="application/xml"/>`)
+	b.WriteString(`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>`)
+	b.WriteString(`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>`)
+	for i := 1; i <= sheets; i++ {
+		fmt.Fprintf(&b, `<Override PartName="/xl/worksheets/sheet%d.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`, i)
+	}
+	b.WriteString(`</Types>`)
+	return b.String()
+}
+
+const rootRels = xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+	`</Relationships>`
+
+func workbook(sheets []sheet) string {
+	var b strings.Builder
+	b.WriteString(xml.Header)
+	b.WriteString(`<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets>`)
+	for i, sh := range sheets {
+		fmt.Fprintf(&b, `<sheet name="%s" sheetId="%d" r:id="rId%d"/>`, escape(sh.name), i+1, i+1)
+	}
+	b.WriteString(`</sheets></workbook>`)
+	return b.String()
+}
+
+func workbookRels(sheets int) string {
+	var b strings.Builder
+	b.WriteString(xml.Header)
+	b.WriteString(`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`)
+	for i := 1; i <= sheets; i++ {
+		fmt.Fprintf(&b, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet%d.xml"/>`, i, i)
+	}
+	fmt.Fprintf(&b, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>`, sheets+1)
+	b.WriteString(`</Relationships>`)
+	return b.String()
+}
+
+// styles has the cellXfs in the order of the style constants
+const styles = xml.Header + `<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+	`<numFmts count="2"><numFmt numFmtId="164" formatCode="yyyy-mm-dd"/><numFmt numFmtId="165" formatCode="&quot;$&quot;#,##0.00;[Red]-&quot;$&quot;#,##0.00"/></numFmts>` +
+	`<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts>` +
+	`<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>` +
+	`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>` +
+	`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>` +
+	`<cellXfs count="4">` +
+	`<xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>` +
+	`<xf numFmtId="164" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>` +
+	`<xf numFmtId="165" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>` +
+	`<xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/>` +
+	`</cellXfs>` +
+	`<cellStyles count="1"><cellStyle name="Normal" xfId="0" builtinId="0"/></cellStyles>` +
+	`</styleSheet>`
@@ -0,0 +1,81 @@
+package xlsx
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/muly/bank-tx/model"
+)
+
+func TestWorksheet(t *testing.T) {
+	s := model.Statement{
+		Institution:      model.BofA,
+		AccountType:      model.CreditCard,
+		AccountNumber:    "4400 1234 5678 1234",
+		PeriodStartDate:  time.Date(2024, 9, 12, 0, 0, 0, 0, time.UTC),
+		PeriodEndDate:    time.Date(2024, 10, 11, 0, 0, 0, 0, time.UTC),
+		BeginningBalance: 10,
+		EndingBalance:    52.75,
+		Transactions: []model.Transaction{
+			{PostingDate: time.Date(2024, 9, 16, 0, 0, 0, 0, time.UTC), Description: "SCHOOL & <CO>", Amount: 42.75, Category: "Purchases"},
+		},
+	}
+
+	sh := statementSheet(s)
+	if sh.name != "bofa 1234 2024-10-11" {
+		t.Errorf("unexpected sheet name %q", sh.name)
+	}
+
+	content := worksheet(sh)
+	if err := xml.Unmarshal([]byte(content), new(interface{})); err != nil {
+		t.Fatalf("invalid xml: %v", err)
+	}
+	for _, expected := range []string{
+		`<pane ySplit="1" topLeftCell="A2" activePane="bottomLeft" state="frozen"/>`,
+		`<c r="A1" s="3" t="inlineStr"><is><t>Transaction Date</t></is></c>`,
+		`<c r="B2" s="1"><v>45551</v></c>`,
+		`<c r="C2" s="0" t="inlineStr"><is><t xml:space="preserve">SCHOOL &amp; &lt;CO&gt;</t></is></c>`,
+		`<c r="G2" s="2"><v>42.75</v></c>`,
+	} {
+		if !strings.Contains(content, expected) {
+			t.Errorf("missing %s in %s", expected, content)
+		}
+	}
+
+	summary := worksheet(summarySheet([]model.Statement{s}))
+	if !strings.Contains(summary, `<c r="E2" s="1"><v>45576</v></c>`) {
+		t.Errorf("missing period end date in %s", summary)
+	}
+	if !strings.Contains(summary, "OK") || !strings.Contains(summary, "Purchases") {
+		t.Errorf("missing validation status or section total in %s", summary)
+	}
+}
+
+func TestUniqueName(t *testing.T) {
+	names := map[string]bool{"summary": true}
+	tests := []struct {
+		name     string
+		expected string
+	}{
+		{"Summary", "Summary (2)"},
+		{"td 7890 2023/04/20", "td 7890 2023_04_20"},
+		{"td 7890 2023/04/20", "td 7890 2023_04_20 (2)"},
+		{"capital_one 1234 2024-01-12 [card]", "capital_one 1234 2024-01-12 _ca"},
+		{"capital_one 1234 2024-01-12 [card]", "capital_one 1234 2024-01-12 (2)"},
+	}
+	for _, tt := range tests {
+		if got := uniqueName(tt.name, names); got != tt.expected {
+			t.Errorf("uniqueName(%q): expected %q, got %q", tt.name, tt.expected, got)
+		}
+	}
+}
+
+func TestColumnName(t *testing.T) {
+	for i, expected := range map[int]string{0: "A", 25: "Z", 26: "AA", 51: "AZ", 52: "BA", 701: "ZZ", 702: "AAA"} {
+		if got := columnName(i); got != expected {
+			t.Errorf("columnName(%d): expected %s, got %s", i, expected, got)
+		}
+	}
+}